@@ -2,24 +2,77 @@ package cmd
 
 import (
 	"fmt"
+	"time"
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/usecase"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	addDue      string
+	addPriority string
+	addTags     []string
+	addParent   int
+	addRepeat   string
+)
+
 var addCmd = &cobra.Command{
 	Use:   "add [task]",
 	Short: "Add a new task",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		err := taskUsecase.Add(args[0])
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var opts []usecase.AddOption
+
+		if addDue != "" {
+			due, err := dateparse.Parse(addDue, time.Now())
+			if err != nil {
+				return usageError(err)
+			}
+			opts = append(opts, usecase.WithDue(due))
+		}
+
+		if addPriority != "" {
+			p, err := domain.ParsePriority(addPriority)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, usecase.WithPriority(p))
+		}
+
+		if len(addTags) > 0 {
+			opts = append(opts, usecase.WithTags(addTags...))
+		}
+
+		if addRepeat != "" {
+			r, err := domain.ParseRecurrence(addRepeat)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, usecase.WithRecurrence(r))
+		}
+
+		if addParent != 0 {
+			opts = append(opts, usecase.WithParent(addParent))
+		}
+
+		task, err := taskUsecase.Add(args[0], opts...)
 		if err != nil {
-			fmt.Println("Error:", err)
-			return
+			return err
 		}
-		fmt.Println("Task added!")
+
+		return render(task, func() {
+			fmt.Println("Task added:", formatTask(task, time.Now()))
+		})
 	},
 }
 
 func init() {
+	addCmd.Flags().StringVar(&addDue, "due", "", `due date, e.g. 2026-11-01, "tomorrow" or "next fri"`)
+	addCmd.Flags().StringVarP(&addPriority, "priority", "p", "", "priority: low, medium or high")
+	addCmd.Flags().StringSliceVarP(&addTags, "tag", "t", nil, "tag to attach (repeatable)")
+	addCmd.Flags().StringVar(&addRepeat, "repeat", "", "repeat rule: daily, weekly[:mon,thu], monthly[:15] or every:3d")
+	addCmd.Flags().IntVar(&addParent, "parent", 0, "ID of the task this is a subtask of")
 	rootCmd.AddCommand(addCmd)
 }
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+	"todo-cli/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+var archiveOlderThan int
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Move completed tasks to the archive",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if archiveOlderThan < 0 {
+			return fmt.Errorf("%w: --older-than must not be negative", domain.ErrInvalidInput)
+		}
+
+		var before time.Time
+		if archiveOlderThan > 0 {
+			before = time.Now().AddDate(0, 0, -archiveOlderThan)
+		}
+
+		moved, err := taskUsecase.Archive(before)
+		if err != nil {
+			return err
+		}
+
+		if moved == nil {
+			moved = []domain.Task{}
+		}
+		return render(moved, func() {
+			fmt.Printf("%d task(s) archived.\n", len(moved))
+		})
+	},
+}
+
+func init() {
+	archiveCmd.Flags().IntVar(&archiveOlderThan, "older-than", 0, "only tasks completed more than this many days ago")
+	rootCmd.AddCommand(archiveCmd)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var blockOn int
+
+var blockCmd = &cobra.Command{
+	Use:   "block <id> --on <other>",
+	Short: "Make a task wait until another task is done",
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+
+		task, err := taskUsecase.Block(id, blockOn)
+		if err != nil {
+			return err
+		}
+
+		return render(task, func() {
+			fmt.Printf("#%d now waits for #%d.\n", id, blockOn)
+		})
+	},
+}
+
+func init() {
+	blockCmd.Flags().IntVar(&blockOn, "on", 0, "ID of the task that must be done first")
+	blockCmd.MarkFlagRequired("on")
+	rootCmd.AddCommand(blockCmd)
+}
@@ -1,32 +1,65 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
+	"os"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/usecase"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	deleteCascade bool
+	deletePromote bool
+)
+
 var deleteCmd = &cobra.Command{
-	Use:   "delete [id]",
-	Short: "Delete task by ID",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		id, err := strconv.Atoi(args[0])
+	Use:   "delete <id>...",
+	Short: "Delete tasks by ID, e.g. delete 3 or delete 1-5,8",
+	Args:  targetArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := targetIDs(args, "delete")
 		if err != nil {
-			fmt.Println("Invalid ID")
-			return
+			return err
+		}
+		if len(ids) == 0 {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+			return nil
 		}
 
-		err = taskUsecase.Delete(id)
+		children := usecase.ChildrenRefuse
+		switch {
+		case deleteCascade && deletePromote:
+			return fmt.Errorf("%w: use either --cascade or --promote", domain.ErrInvalidInput)
+		case deleteCascade:
+			children = usecase.ChildrenDelete
+		case deletePromote:
+			children = usecase.ChildrenPromote
+		}
+
+		removed, err := taskUsecase.Delete(ids, children)
+		if errors.Is(err, usecase.ErrHasChildren) {
+			return fmt.Errorf("%w; use --cascade to delete the subtasks too or --promote to keep them", err)
+		}
 		if err != nil {
-			fmt.Println("Error:", err)
-			return
+			return err
 		}
-		fmt.Println("Task deleted!")
+
+		return render(removed, func() {
+			if len(removed) == 1 {
+				fmt.Println("Task deleted!")
+			} else {
+				fmt.Printf("%d tasks deleted!\n", len(removed))
+			}
+		})
 	},
 }
 
 func init() {
+	deleteCmd.Flags().BoolVarP(&deleteCascade, "cascade", "r", false, "also delete all subtasks")
+	deleteCmd.Flags().BoolVar(&deletePromote, "promote", false, "move subtasks up to the deleted task's parent")
+	addMatchFlags(deleteCmd)
 	rootCmd.AddCommand(deleteCmd)
 }
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+	"todo-cli/internal/usecase"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorFix bool
+
+// doctorReport is the result of a check, with the data file's layout
+// version before any fix.
+type doctorReport struct {
+	File           string            `json:"file"`
+	Version        int               `json:"version"`
+	CurrentVersion int               `json:"current_version"`
+	Problems       []usecase.Problem `json:"problems"`
+	Fixed          bool              `json:"fixed"`
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the data file for problems and optionally repair them",
+	Long: `Check the current list's data file for tasks with missing or duplicate
+IDs, missing names, and parent or dependency links to missing tasks or
+in a loop. With --fix the problems are repaired in one change that can be
+undone, and a file in an older layout is saved in the current one.
+Repairing tasks that share an ID cannot be undone and clears the undo
+history.
+
+Exits with code 4 if problems were found and not fixed.`,
+	Args: noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := repository.FileVersion(dataPath, listCipher)
+		if err != nil {
+			return err
+		}
+
+		problems, err := taskUsecase.Check(doctorFix)
+		if err != nil {
+			return err
+		}
+
+		report := doctorReport{
+			File:           dataPath,
+			Version:        version,
+			CurrentVersion: repository.CurrentVersion,
+			Problems:       problems,
+			Fixed:          doctorFix,
+		}
+		if report.Problems == nil {
+			report.Problems = []usecase.Problem{}
+		}
+
+		err = render(report, func() {
+			outdated := version < repository.CurrentVersion
+			switch {
+			case outdated && doctorFix:
+				fmt.Printf("%s: upgraded from format version %d to %d\n", dataPath, version, repository.CurrentVersion)
+			case outdated:
+				fmt.Printf("%s: format version %d, --fix upgrades it to %d\n", dataPath, version, repository.CurrentVersion)
+			default:
+				fmt.Printf("%s: format version %d\n", dataPath, version)
+			}
+
+			for _, p := range problems {
+				fmt.Printf("#%d: %s (%s)\n", p.ID, p.Problem, p.Fix)
+			}
+
+			switch {
+			case len(problems) == 0:
+				fmt.Println("No problems found.")
+			case doctorFix:
+				fmt.Printf("Fixed %d problems.\n", len(problems))
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(problems) > 0 && !doctorFix {
+			return fmt.Errorf("%w: %d problems found, run 'todo doctor --fix' to repair them", domain.ErrConflict, len(problems))
+		}
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "repair the problems found")
+	rootCmd.AddCommand(doctorCmd)
+}
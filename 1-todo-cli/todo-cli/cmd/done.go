@@ -2,31 +2,51 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
+	"os"
+	"todo-cli/internal/dateparse"
 
 	"github.com/spf13/cobra"
 )
 
+var doneCascade bool
+
 var doneCmd = &cobra.Command{
-	Use:   "done [id]",
-	Short: "Mark task as done",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		id, err := strconv.Atoi(args[0])
+	Use:   "done <id>...",
+	Short: "Mark tasks as done, e.g. done 3 or done 1-5,8",
+	Args:  targetArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := targetIDs(args, "mark as done")
 		if err != nil {
-			fmt.Println("Invalid ID")
-			return
+			return err
+		}
+		if len(ids) == 0 {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+			return nil
 		}
 
-		err = taskUsecase.MarkDone(id)
+		result, err := taskUsecase.MarkDone(ids, doneCascade)
 		if err != nil {
-			fmt.Println("Error:", err)
-			return
+			return err
 		}
-		fmt.Println("Task marked as done!")
+
+		return render(result, func() {
+			switch len(result.Completed) {
+			case 0:
+				fmt.Println("Nothing changed: already done.")
+			case 1:
+				fmt.Println("Task marked as done!")
+			default:
+				fmt.Printf("%d tasks marked as done!\n", len(result.Completed))
+			}
+			for _, t := range result.Next {
+				fmt.Printf("Next occurrence: #%d %s due %s\n", t.ID, t.Name, dateparse.Format(*t.Due))
+			}
+		})
 	},
 }
 
 func init() {
+	doneCmd.Flags().BoolVarP(&doneCascade, "cascade", "r", false, "also mark all subtasks as done")
+	addMatchFlags(doneCmd)
 	rootCmd.AddCommand(doneCmd)
 }
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <id> <new name>",
+	Short: "Rename a task",
+	Args:  minArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+
+		task, err := taskUsecase.Rename(id, strings.Join(args[1:], " "))
+		if err != nil {
+			return err
+		}
+
+		return render(task, func() {
+			fmt.Printf("Task #%d renamed to %q.\n", task.ID, task.Name)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
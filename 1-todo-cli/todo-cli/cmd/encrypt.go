@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+
+	"github.com/spf13/cobra"
+)
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the current list with a passphrase",
+	Long: "Encrypt the current list, its undo history and archive with a key derived\n" +
+		"from a passphrase. The passphrase is prompted for, or read from $" + passphraseEnv + ".",
+	Args: noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if listCipher != nil {
+			return fmt.Errorf("%w: %s is already encrypted", domain.ErrConflict, currentList())
+		}
+
+		passphrase, err := newPassphrase()
+		if err != nil {
+			return err
+		}
+
+		c := repository.NewCipher(passphrase)
+		if err := convertList(func(path string) error { return repository.SealFile(path, c) }); err != nil {
+			return err
+		}
+
+		fmt.Printf("List %s encrypted.\n", currentList())
+		return nil
+	},
+}
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Store the current list unencrypted again",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if listCipher == nil {
+			return fmt.Errorf("%w: %s is not encrypted", domain.ErrConflict, currentList())
+		}
+
+		// Opening the data file first checks the passphrase.
+		if _, err := taskUsecase.List(); err != nil {
+			return err
+		}
+		if err := convertList(func(path string) error { return repository.UnsealFile(path, listCipher) }); err != nil {
+			return err
+		}
+
+		fmt.Printf("List %s decrypted.\n", currentList())
+		return nil
+	},
+}
+
+// convertList rewrites every file of the current list while holding its lock.
+func convertList(convert func(path string) error) (err error) {
+	unlock, err := taskRepo.Lock()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if uerr := unlock(); err == nil {
+			err = uerr
+		}
+	}()
+
+	files, err := listFiles()
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		if err := convert(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(encryptCmd)
+	rootCmd.AddCommand(decryptCmd)
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes, one per error class, so scripts can react to failures.
+const (
+	exitOK       = 0
+	exitError    = 1 // unexpected failure
+	exitUsage    = 2 // bad arguments, flags or input data
+	exitNotFound = 3 // no task with that ID
+	exitConflict = 4 // not possible in the current state
+	exitStorage  = 5 // data file corrupt, locked, not decryptable or too new
+)
+
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, domain.ErrInvalidInput):
+		return exitUsage
+	case errors.Is(err, domain.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return exitConflict
+	case errors.Is(err, repository.ErrCorrupt), errors.Is(err, repository.ErrLocked),
+		errors.Is(err, repository.ErrDecrypt), errors.Is(err, repository.ErrEncrypted),
+		errors.Is(err, repository.ErrNotEncrypted), errors.Is(err, repository.ErrUnsupportedVersion):
+		return exitStorage
+	case strings.HasPrefix(err.Error(), "unknown command"),
+		strings.HasPrefix(err.Error(), "required flag"):
+		// cobra reports unknown subcommands and missing
+		// required flags as plain errors.
+		return exitUsage
+	default:
+		return exitError
+	}
+}
+
+// printError reports err on stderr in the selected output format.
+func printError(err error) {
+	if outputFormat == outputJSON || outputFormat == outputYAML {
+		payload := map[string]any{"error": err.Error(), "code": exitCode(err)}
+		if outputFormat == outputJSON {
+			writeJSON(os.Stderr, payload)
+		} else {
+			writeYAML(os.Stderr, payload)
+		}
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Error:", err)
+}
+
+func usageError(err error) error {
+	return fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+}
+
+// exactArgs is cobra.ExactArgs reporting a usage error.
+func exactArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(n)(cmd, args); err != nil {
+			return usageError(err)
+		}
+		return nil
+	}
+}
+
+// minArgs is cobra.MinimumNArgs reporting a usage error.
+func minArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := cobra.MinimumNArgs(n)(cmd, args); err != nil {
+			return usageError(err)
+		}
+		return nil
+	}
+}
+
+// noArgs is cobra.NoArgs reporting a usage error.
+func noArgs(cmd *cobra.Command, args []string) error {
+	if err := cobra.NoArgs(cmd, args); err != nil {
+		return usageError(err)
+	}
+	return nil
+}
+
+func parseID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: task ID %q", domain.ErrInvalidInput, s)
+	}
+	return id, nil
+}
+
+// maxRange caps a single ID range so a typo cannot expand to millions of IDs.
+const maxRange = 10000
+
+// parseIDs parses task IDs given as separate arguments and/or comma
+// separated lists of IDs and ranges, e.g. "1-5,8 12". Duplicates are dropped.
+// ranged holds the IDs that were only given as part of a range.
+func parseIDs(args []string) (ids []int, ranged map[int]bool, err error) {
+	seen := map[int]bool{}
+	explicit := map[int]bool{}
+	ranged = map[int]bool{}
+	add := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, arg := range args {
+		for _, part := range strings.Split(arg, ",") {
+			lo, hi, isRange := strings.Cut(part, "-")
+			if !isRange {
+				id, err := parseID(part)
+				if err != nil {
+					return nil, nil, err
+				}
+				add(id)
+				explicit[id] = true
+				continue
+			}
+
+			from, err := parseID(lo)
+			if err != nil {
+				return nil, nil, err
+			}
+			to, err := parseID(hi)
+			if err != nil {
+				return nil, nil, err
+			}
+			if to < from || to-from >= maxRange {
+				return nil, nil, fmt.Errorf("%w: ID range %q", domain.ErrInvalidInput, part)
+			}
+			for id := from; id <= to; id++ {
+				add(id)
+				ranged[id] = true
+			}
+		}
+	}
+
+	for id := range explicit {
+		delete(ranged, id)
+	}
+	return ids, ranged, nil
+}
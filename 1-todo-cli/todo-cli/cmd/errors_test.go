@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+	"todo-cli/internal/usecase"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, exitOK},
+		{errors.New("boom"), exitError},
+		{fmt.Errorf("%w: task ID %q", domain.ErrInvalidInput, "x"), exitUsage},
+		{fmt.Errorf("%w: #3", domain.ErrNotFound), exitNotFound},
+		{usecase.ErrParentNotFound, exitNotFound},
+		{usecase.ErrHasChildren, exitConflict},
+		{usecase.ErrNothingToUndo, exitConflict},
+		{fmt.Errorf("%w: tasks.json", repository.ErrLocked), exitStorage},
+		{fmt.Errorf("%w: tasks.json", repository.ErrUnsupportedVersion), exitStorage},
+		{errors.New(`unknown command "bogus" for "todo"`), exitUsage},
+		{errors.New(`required flag(s) "on" not set`), exitUsage},
+	}
+
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Fatalf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestParseIDs(t *testing.T) {
+	ids, ranged, err := parseIDs([]string{"1-3,8", "12", "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int{1, 2, 3, 8, 12}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("parseIDs = %v, want %v", ids, want)
+	}
+	if want := map[int]bool{1: true, 3: true}; !reflect.DeepEqual(ranged, want) {
+		t.Fatalf("ranged = %v, want %v", ranged, want)
+	}
+
+	for _, bad := range []string{"5-3", "x", "1,,2", "0", "1-", "1-100000"} {
+		if _, _, err := parseIDs([]string{bad}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("parseIDs(%q): expected ErrInvalidInput, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"todo-cli/internal/format"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportFile   string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export tasks as todo.txt, CSV, Markdown or JSON",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := format.ByName(exportFormat)
+		if err != nil {
+			return usageError(err)
+		}
+
+		tasks, err := taskUsecase.List()
+		if err != nil {
+			return err
+		}
+
+		if exportFile == "" {
+			return f.Encode(os.Stdout, tasks)
+		}
+
+		out, err := os.Create(exportFile)
+		if err != nil {
+			return err
+		}
+
+		err = f.Encode(out, tasks)
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Exported %d tasks to %s\n", len(tasks), exportFile)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "json", "output format: "+strings.Join(format.Names(), ", "))
+	exportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "write to this file instead of stdout")
+	rootCmd.AddCommand(exportCmd)
+}
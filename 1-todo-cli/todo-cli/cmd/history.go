@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"todo-cli/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recent changes, newest first",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		journal, err := taskUsecase.History()
+		if err != nil {
+			return err
+		}
+
+		return render(journal, func() {
+			if len(journal.Operations) == 0 {
+				fmt.Println("No history yet.")
+				return
+			}
+
+			for i := len(journal.Operations) - 1; i >= 0; i-- {
+				op := journal.Operations[i]
+
+				marker := " "
+				if i >= journal.Cursor {
+					marker = "↶" // undone, can be redone
+				}
+
+				fmt.Printf("%s %s  %s\n", marker, op.Time.Format("2006-01-02 15:04"), describeOperation(op))
+			}
+		})
+	},
+}
+
+// describeOperation renders an operation as e.g. "delete #3 Write docs".
+func describeOperation(op domain.Operation) string {
+	var tasks []string
+	for _, c := range op.Changes {
+		t := c.Task()
+		tasks = append(tasks, fmt.Sprintf("#%d %s", t.ID, t.Name))
+	}
+	if len(tasks) == 0 {
+		return op.Name // a pure reorder
+	}
+	return op.Name + " " + strings.Join(tasks, ", ")
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"todo-cli/internal/format"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat string
+	importDryRun bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import tasks from a todo.txt, CSV, Markdown or JSON file",
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+
+		var f format.Format
+		var err error
+		if importFormat != "" {
+			f, err = format.ByName(importFormat)
+		} else {
+			f, err = format.ForFile(path)
+		}
+		if err != nil {
+			return usageError(err)
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		incoming, err := f.Decode(in)
+		if err != nil {
+			return usageError(fmt.Errorf("%s: %w", path, err))
+		}
+
+		result, err := taskUsecase.Import(incoming, importDryRun)
+		if err != nil {
+			return err
+		}
+
+		return render(result, func() {
+			now := time.Now()
+			for i, t := range result.Added {
+				fmt.Printf("%d. %s\n", i+1, formatTask(t, now))
+			}
+			for _, t := range result.Skipped {
+				fmt.Printf("skipped (already exists): %s\n", t.Name)
+			}
+
+			if importDryRun {
+				fmt.Printf("Dry run: %d tasks would be added, %d skipped\n", len(result.Added), len(result.Skipped))
+				return
+			}
+			fmt.Printf("Imported %d tasks, %d skipped\n", len(result.Added), len(result.Skipped))
+		})
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFormat, "format", "", "input format (default: from file extension): "+strings.Join(format.Names(), ", "))
+	importCmd.Flags().BoolVarP(&importDryRun, "dry-run", "n", false, "show what would be added without saving")
+	rootCmd.AddCommand(importCmd)
+}
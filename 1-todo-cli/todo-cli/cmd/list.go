@@ -2,30 +2,152 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
+	"time"
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/usecase"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	listTag     string
+	listStatus  string
+	listOverdue bool
+	listSort    string
+	listAll     bool
+)
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all tasks",
-	Run: func(cmd *cobra.Command, args []string) {
-		tasks, err := taskUsecase.List()
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := usecase.ListFilter{
+			Tag:      listTag,
+			Overdue:  listOverdue,
+			Archived: listAll,
+		}
+
+		switch listStatus {
+		case "all":
+			filter.Status = usecase.StatusAll
+		case "open":
+			filter.Status = usecase.StatusOpen
+		case "done":
+			filter.Status = usecase.StatusDone
+		default:
+			return fmt.Errorf("%w: --status must be all, open or done", domain.ErrInvalidInput)
+		}
+
+		switch listSort {
+		case "":
+			filter.Sort = usecase.SortNone
+		case "due":
+			filter.Sort = usecase.SortDue
+		case "priority":
+			filter.Sort = usecase.SortPriority
+		default:
+			return fmt.Errorf("%w: --sort must be due or priority", domain.ErrInvalidInput)
+		}
+
+		roots, err := taskUsecase.Tree(filter)
 		if err != nil {
-			fmt.Println("Error:", err)
-			return
+			return err
 		}
 
-		for _, t := range tasks {
-			status := " "
-			if t.Done {
-				status = "✓"
+		// The position is only a display alias;
+		// commands take the permanent ID shown after '#'.
+		var tasks []domain.Task
+		var lines []string
+		now := time.Now()
+		var walk func(nodes []*domain.TaskNode, depth int)
+		walk = func(nodes []*domain.TaskNode, depth int) {
+			for _, n := range nodes {
+				tasks = append(tasks, n.Task)
+				line := fmt.Sprintf("%d. %s%s", len(tasks), strings.Repeat("   ", depth), formatTask(n.Task, now))
+				if n.TotalChildren > 0 {
+					line += fmt.Sprintf(" [%d/%d]", n.DoneChildren, n.TotalChildren)
+				}
+				if len(n.Blockers) > 0 {
+					line += " [blocked by " + formatIDs(n.Blockers) + "]"
+				}
+				lines = append(lines, line)
+				walk(n.Children, depth+1)
 			}
-			fmt.Printf("[%s] %d: %s\n", status, t.ID, t.Name)
 		}
+		walk(roots, 0)
+
+		if tasks == nil {
+			tasks = []domain.Task{}
+		}
+		return render(tasks, func() {
+			for _, line := range lines {
+				fmt.Println(line)
+			}
+		})
 	},
 }
 
+// formatTask renders a task without its list position, e.g.
+// "[ ] #7 Deploy (high, due 2026-11-01) +ops".
+func formatTask(t domain.Task, now time.Time) string {
+	status := " "
+	if t.Done {
+		status = "✓"
+	}
+
+	line := fmt.Sprintf("[%s] #%d %s", status, t.ID, t.Name)
+
+	var meta []string
+	if t.Priority != domain.PriorityNone {
+		meta = append(meta, t.Priority.String())
+	}
+	if t.Due != nil {
+		due := "due " + dateparse.Format(*t.Due)
+		if t.IsOverdue(now) {
+			due += " OVERDUE"
+		}
+		meta = append(meta, due)
+	}
+	if t.Recurrence != nil {
+		meta = append(meta, "repeats "+t.Recurrence.String())
+	}
+	if t.Running() {
+		meta = append(meta, "⏱ "+formatDuration(t.Tracked(now))+" running")
+	} else if len(t.Time) > 0 {
+		meta = append(meta, "⏱ "+formatDuration(t.Tracked(now)))
+	}
+	if len(meta) > 0 {
+		line += " (" + strings.Join(meta, ", ") + ")"
+	}
+
+	for _, tag := range t.Tags {
+		if strings.HasPrefix(tag, "@") {
+			line += " " + tag // context
+		} else {
+			line += " +" + tag
+		}
+	}
+
+	return line
+}
+
+// formatIDs renders IDs as "#3, #5".
+func formatIDs(ids []int) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(s, ", ")
+}
+
 func init() {
+	listCmd.Flags().StringVarP(&listTag, "tag", "t", "", "only tasks with this tag")
+	listCmd.Flags().StringVarP(&listStatus, "status", "s", "all", "filter by status: all, open or done")
+	listCmd.Flags().BoolVar(&listOverdue, "overdue", false, "only open tasks past their due date")
+	listCmd.Flags().StringVar(&listSort, "sort", "", "sort by: due or priority")
+	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "include archived tasks")
 	rootCmd.AddCommand(listCmd)
 }
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+
+	"github.com/spf13/cobra"
+)
+
+var listsCmd = &cobra.Command{
+	Use:   "lists",
+	Short: "Show the named task lists",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := cfg.Lists()
+		if err != nil {
+			return err
+		}
+
+		current := currentList()
+		if !slices.Contains(names, current) {
+			names = append(names, current) // selected but still empty
+			slices.Sort(names)
+		}
+
+		var infos []listInfo
+		for _, name := range names {
+			path, err := cfg.ListPath(name)
+			if err != nil {
+				return err
+			}
+
+			info := listInfo{Name: name, Path: path, Current: name == current}
+
+			// Other encrypted lists are not opened; that needs their passphrase.
+			var tasks []domain.Task
+			if info.Current {
+				tasks, err = taskUsecase.List()
+			} else {
+				tasks, err = (&repository.JSONRepository{Filename: path}).Load()
+			}
+			if errors.Is(err, repository.ErrEncrypted) {
+				info.Encrypted = true
+			} else if err != nil {
+				info.Error = err.Error()
+			}
+			for _, t := range tasks {
+				if !t.Done {
+					info.Open++
+				}
+			}
+			info.Total = len(tasks)
+
+			infos = append(infos, info)
+		}
+
+		return render(infos, func() {
+			fmt.Println("Lists in", cfg.DataDir)
+			for _, info := range infos {
+				marker := " "
+				if info.Current {
+					marker = "*"
+				}
+				if info.Error != "" {
+					fmt.Printf("%s %s (error: %s)\n", marker, info.Name, info.Error)
+					continue
+				}
+				if info.Encrypted {
+					fmt.Printf("%s %s (encrypted)\n", marker, info.Name)
+					continue
+				}
+				fmt.Printf("%s %s (%d open, %d total)\n", marker, info.Name, info.Open, info.Total)
+			}
+		})
+	},
+}
+
+type listInfo struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Current bool   `json:"current"`
+	Open    int    `json:"open"`
+	Total   int    `json:"total"`
+	Error   string `json:"error,omitempty"`
+
+	Encrypted bool `json:"encrypted,omitempty"`
+}
+
+func init() {
+	rootCmd.AddCommand(listsCmd)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+	"todo-cli/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+var nextLimit int
+
+var nextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Show the most important tasks you can work on now",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tasks, err := taskUsecase.Next(nextLimit)
+		if err != nil {
+			return err
+		}
+
+		if tasks == nil {
+			tasks = []domain.Task{}
+		}
+		return render(tasks, func() {
+			if len(tasks) == 0 {
+				fmt.Println("Nothing to do.")
+				return
+			}
+			now := time.Now()
+			for _, t := range tasks {
+				fmt.Println(formatTask(t, now))
+			}
+		})
+	},
+}
+
+func init() {
+	nextCmd.Flags().IntVarP(&nextLimit, "limit", "n", 5, "number of tasks to show, 0 for all")
+	rootCmd.AddCommand(nextCmd)
+}
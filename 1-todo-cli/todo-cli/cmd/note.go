@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"todo-cli/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	noteMessage string
+	noteLinks   []string
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note <id>",
+	Short: "Edit the note and links of a task in $EDITOR",
+	Long: `Edit the note and links of a task in $VISUAL or $EDITOR (default vi).
+
+The note goes above the "--- links" line, one URL or file path per line
+below it. With --message or --link the task is changed directly instead.
+Encrypted lists need --message or --link, as the editor would get the
+note as a plain file.`,
+	Args: exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+
+		task, err := taskUsecase.Get(id)
+		if err != nil {
+			return err
+		}
+
+		note, links := task.Note, task.Links
+		if cmd.Flags().Changed("message") || len(noteLinks) > 0 {
+			if cmd.Flags().Changed("message") {
+				note = noteMessage
+			}
+			links = append(slices.Clone(links), noteLinks...)
+		} else {
+			if listCipher != nil {
+				return fmt.Errorf("%w: list %s is encrypted and the editor would get the note as a plain file; use --message or --link", domain.ErrInvalidInput, currentList())
+			}
+			edited, err := editText(formatNoteFile(task.ID, task.Name, note, links))
+			if err != nil {
+				return err
+			}
+			note, links = parseNoteFile(edited)
+		}
+
+		task, err = taskUsecase.SetNote(id, note, links)
+		if err != nil {
+			return err
+		}
+
+		return render(task, func() {
+			fmt.Printf("Note of #%d saved.\n", task.ID)
+		})
+	},
+}
+
+// linksMarker separates the note from the links in the edited file.
+const linksMarker = "--- links"
+
+// formatNoteFile lays out a note and its links for editing.
+func formatNoteFile(id int, name, note string, links []string) string {
+	var b strings.Builder
+	if note != "" {
+		b.WriteString(note + "\n")
+	}
+	b.WriteString("\n" + linksMarker + " (one URL or file path per line) ---\n")
+	for _, l := range links {
+		b.WriteString(l + "\n")
+	}
+	fmt.Fprintf(&b, "# Note and links of #%d %s.\n", id, name)
+	b.WriteString("# Lines starting with '#' below the links line are ignored.\n")
+	return b.String()
+}
+
+// parseNoteFile reads back a file laid out by formatNoteFile.
+// Without the links line everything is the note.
+func parseNoteFile(s string) (note string, links []string) {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+
+	i := slices.IndexFunc(lines, func(l string) bool {
+		return strings.HasPrefix(strings.TrimSpace(l), linksMarker)
+	})
+	if i < 0 {
+		return strings.TrimSpace(s), nil
+	}
+
+	for _, l := range lines[i+1:] {
+		if l = strings.TrimSpace(l); l != "" && !strings.HasPrefix(l, "#") {
+			links = append(links, l)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines[:i], "\n")), links
+}
+
+// editText lets the user edit text in their editor and returns the result.
+func editText(text string) (string, error) {
+	f, err := os.CreateTemp("", "todo-note-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	editor := editorCommand()
+	c := exec.Command(editor[0], append(editor[1:], f.Name())...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("editor %s: %w", editor[0], err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// editorCommand is $VISUAL or $EDITOR split into arguments,
+// so that e.g. "code --wait" works.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(env)); len(editor) > 0 {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+func init() {
+	noteCmd.Flags().StringVarP(&noteMessage, "message", "m", "", "set the note to this text instead of opening the editor")
+	noteCmd.Flags().StringArrayVarP(&noteLinks, "link", "l", nil, "add a URL or file path (repeatable)")
+	rootCmd.AddCommand(noteCmd)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNoteFile_RoundTrip(t *testing.T) {
+	note := "# Plan\n\nCall Bob first.\n  - then Alice"
+	links := []string{"https://example.com/a b", "~/docs/plan.md"}
+
+	gotNote, gotLinks := parseNoteFile(formatNoteFile(4, "Write report", note, links))
+	if gotNote != note {
+		t.Fatalf("expected note %q, got %q", note, gotNote)
+	}
+	if !reflect.DeepEqual(gotLinks, links) {
+		t.Fatalf("expected links %v, got %v", links, gotLinks)
+	}
+
+	// Without the links line everything is the note.
+	if note, links := parseNoteFile("just text\n# not a comment\n"); note != "just text\n# not a comment" || links != nil {
+		t.Fatalf("unexpected parse: %q %v", note, links)
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+
+	"golang.org/x/term"
+)
+
+// passphraseEnv supplies the passphrase of encrypted lists without a prompt.
+const passphraseEnv = "TODO_PASSPHRASE"
+
+// readPassphrase takes the passphrase from $TODO_PASSPHRASE or
+// asks for it on the terminal without echoing it.
+func readPassphrase(prompt string) (string, error) {
+	if p := os.Getenv(passphraseEnv); p != "" {
+		return p, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("%w; set %s", repository.ErrEncrypted, passphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	p, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(p), nil
+}
+
+// newPassphrase is readPassphrase for a passphrase being set, which is
+// asked for twice when prompted.
+func newPassphrase() (string, error) {
+	p, err := readPassphrase("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if p == "" {
+		return "", fmt.Errorf("%w: empty passphrase", domain.ErrInvalidInput)
+	}
+
+	if os.Getenv(passphraseEnv) == "" {
+		again, err := readPassphrase("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != p {
+			return "", fmt.Errorf("%w: passphrases do not match", domain.ErrInvalidInput)
+		}
+	}
+	return p, nil
+}
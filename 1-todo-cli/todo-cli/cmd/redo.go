@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Re-apply the last undone change",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		op, err := taskUsecase.Redo()
+		if err != nil {
+			return err
+		}
+
+		return render(op, func() {
+			fmt.Println("Redone:", describeOperation(op))
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(redoCmd)
+}
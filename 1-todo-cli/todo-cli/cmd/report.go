@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/usecase"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportSince string
+	reportBy    string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Total the time tracked per task, tag or day",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, err := dateparse.Since(reportSince, time.Now())
+		if err != nil {
+			return fmt.Errorf("%w: --since: %v", domain.ErrInvalidInput, err)
+		}
+
+		var by usecase.ReportBy
+		switch reportBy {
+		case "task":
+			by = usecase.ByTask
+		case "tag":
+			by = usecase.ByTag
+		case "day":
+			by = usecase.ByDay
+		default:
+			return fmt.Errorf("%w: --by must be task, tag or day", domain.ErrInvalidInput)
+		}
+
+		report, err := taskUsecase.Report(since, by)
+		if err != nil {
+			return err
+		}
+
+		return render(report, func() {
+			fmt.Printf("Time tracked since %s\n", dateparse.Format(report.Since))
+			width := 5
+			for _, r := range report.Rows {
+				width = max(width, len([]rune(r.Key)))
+			}
+			for _, r := range report.Rows {
+				fmt.Printf("  %-*s  %8s\n", width, r.Key, formatDuration(time.Duration(r.Seconds)*time.Second))
+			}
+			fmt.Printf("  %-*s  %8s\n", width, "Total", formatDuration(time.Duration(report.Total)*time.Second))
+		})
+	},
+}
+
+// formatDuration renders d to the minute, e.g. "2h05m" or "25m".
+func formatDuration(d time.Duration) string {
+	m := int(d.Round(time.Minute) / time.Minute)
+	if m < 60 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportSince, "since", "7d", "start of the period: 7d, 2w, 1m or a date")
+	reportCmd.Flags().StringVar(&reportBy, "by", "task", "group by: task, tag or day")
+	rootCmd.AddCommand(reportCmd)
+}
@@ -1,26 +1,123 @@
 package cmd
 
 import (
-	"github.com/spf13/cobra"
+	"fmt"
 	"os"
+	"todo-cli/internal/config"
 	"todo-cli/internal/repository"
 	"todo-cli/internal/usecase"
+
+	"github.com/spf13/cobra"
 )
 
-var taskUsecase *usecase.TaskUsecase
+var (
+	taskUsecase *usecase.TaskUsecase
+	taskRepo    repository.TaskRepository
+
+	// dataPath is the selected list's data file; listCipher is
+	// set when that file is encrypted.
+	dataPath   string
+	listCipher *repository.Cipher
+
+	cfg        config.Config
+	configPath string
+	listFlag   string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "todo",
 	Short: "A simple CLI Todo application to manage your tasks",
+	// Errors are printed by Execute in the selected output format.
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setup()
+	},
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		printError(err)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
-	repo := &repository.JSONRepository{Filename: "tasks.json"}
-	taskUsecase = usecase.NewTaskUsecase(repo)
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError(err)
+	})
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputTable, "output format: table, json or yaml")
+	rootCmd.PersistentFlags().StringVarP(&listFlag, "list", "L", "", "named task list to use (default from config)")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "config file (default $XDG_CONFIG_HOME/todo/config.json)")
+}
+
+// setup loads the config and wires the usecase to the selected list.
+func setup() error {
+	if err := checkOutputFormat(); err != nil {
+		return err
+	}
+
+	path := configPath
+	if path == "" {
+		var err error
+		if path, err = config.Path(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if cfg, err = config.Load(path); err != nil {
+		return err
+	}
+
+	name := listFlag
+	if name == "" {
+		name = cfg.DefaultList
+	}
+
+	if dataPath, err = cfg.ListPath(name); err != nil {
+		return usageError(err)
+	}
+	if err := os.MkdirAll(cfg.DataDir, 0755); err != nil {
+		return err
+	}
+
+	encrypted, err := repository.IsEncryptedFile(dataPath)
+	if err != nil {
+		return err
+	}
+
+	listCipher = nil
+	taskRepo = &repository.JSONRepository{Filename: dataPath}
+	if encrypted {
+		passphrase, err := readPassphrase(fmt.Sprintf("Passphrase for %s: ", name))
+		if err != nil {
+			return err
+		}
+		listCipher = repository.NewCipher(passphrase)
+		taskRepo = &repository.EncryptedRepository{Filename: dataPath, Cipher: listCipher}
+	}
+
+	journal := &repository.JSONJournal{Filename: config.JournalPath(dataPath), Cipher: listCipher}
+	archive := &repository.JSONArchive{Filename: config.ArchivePath(dataPath), Cipher: listCipher}
+	taskUsecase = usecase.NewTaskUsecase(taskRepo, usecase.WithJournal(journal), usecase.WithArchive(archive))
+	return nil
+}
+
+// listFiles returns the files of the selected list, the data file last.
+func listFiles() ([]string, error) {
+	bases, err := config.SyncBasePaths(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	files := append([]string{config.JournalPath(dataPath), config.ArchivePath(dataPath)}, bases...)
+	return append(files, dataPath), nil
+}
+
+// currentList is the name of the list selected by --list or the config.
+func currentList() string {
+	if listFlag != "" {
+		return listFlag
+	}
+	return cfg.DefaultList
 }
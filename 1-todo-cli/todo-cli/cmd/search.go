@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/usecase"
+
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find tasks by fuzzy matching name, tags and note",
+	Args:  minArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results, err := taskUsecase.Search(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+
+		if results == nil {
+			results = []usecase.SearchResult{}
+		}
+		return render(results, func() {
+			if len(results) == 0 {
+				fmt.Println("No matching tasks.")
+				return
+			}
+			now := time.Now()
+			for _, r := range results {
+				fmt.Println(formatMatch(r, now))
+			}
+		})
+	},
+}
+
+// formatMatch is formatTask with the matched characters of the name highlighted.
+func formatMatch(r usecase.SearchResult, now time.Time) string {
+	t := r.Task
+	if colorEnabled() {
+		t.Name = highlight(t.Name, r.Positions)
+	}
+	return formatTask(t, now)
+}
+
+// highlight wraps the runes of s at the given positions in bold yellow.
+func highlight(s string, positions []int) string {
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p] = true
+	}
+
+	var b strings.Builder
+	on := false
+	for i, r := range []rune(s) {
+		if marked[i] != on {
+			on = marked[i]
+			if on {
+				b.WriteString("\x1b[1;33m")
+			} else {
+				b.WriteString("\x1b[0m")
+			}
+		}
+		b.WriteRune(r)
+	}
+	if on {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// colorEnabled reports whether stdout is a terminal and NO_COLOR is unset.
+func colorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// stdin is where confirmations are read from.
+var stdin io.Reader = os.Stdin
+
+var (
+	matchQuery string
+	matchYes   bool
+)
+
+// addMatchFlags lets a command select its tasks with --match instead of IDs.
+func addMatchFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&matchQuery, "match", "m", "", "select tasks by fuzzy search instead of ID")
+	cmd.Flags().BoolVarP(&matchYes, "yes", "y", false, "with --match, act on all matches without asking")
+}
+
+// pickMatches resolves --match to task IDs. A single match is used as is;
+// with several the user picks one or all of them on stdin, unless --yes
+// selects all. An empty result means the user cancelled.
+func pickMatches(action string) ([]int, error) {
+	results, err := taskUsecase.Search(matchQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(results) == 0:
+		return nil, fmt.Errorf("%w: no task matches %q", domain.ErrNotFound, matchQuery)
+	case len(results) == 1 || matchYes:
+		return resultIDs(results), nil
+	}
+
+	// Prompts go to stderr so stdout stays clean for -o json.
+	now := time.Now()
+	fmt.Fprintf(os.Stderr, "%d tasks match %q:\n", len(results), matchQuery)
+	for i, r := range results {
+		fmt.Fprintf(os.Stderr, "%d. %s\n", i+1, formatTask(r.Task, now))
+	}
+	fmt.Fprintf(os.Stderr, "Which one to %s? [1-%d, a for all, Enter to cancel]: ", action, len(results))
+
+	answer, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	switch answer {
+	case "":
+		return nil, nil
+	case "a", "all":
+		return resultIDs(results), nil
+	}
+
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(results) {
+		return nil, fmt.Errorf("%w: expected a number from 1 to %d, got %q", domain.ErrInvalidInput, len(results), answer)
+	}
+	return []int{results[n-1].Task.ID}, nil
+}
+
+// targetArgs accepts either --match or at least one ID argument.
+func targetArgs(cmd *cobra.Command, args []string) error {
+	if matchQuery != "" {
+		return noArgs(cmd, args)
+	}
+	return minArgs(1)(cmd, args)
+}
+
+// targetIDs returns the tasks a command acts on, from --match or the
+// ID arguments. An empty result means the user cancelled the pick.
+// IDs that are only part of a range are skipped when there is no such
+// task, since deleted IDs are not reused; other IDs must exist.
+func targetIDs(args []string, action string) ([]int, error) {
+	if matchQuery != "" {
+		return pickMatches(action)
+	}
+
+	ids, ranged, err := parseIDs(args)
+	if err != nil || len(ranged) == 0 {
+		return ids, err
+	}
+
+	tasks, err := taskUsecase.List()
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[int]bool, len(tasks))
+	for _, t := range tasks {
+		exists[t.ID] = true
+	}
+
+	ids = slices.DeleteFunc(ids, func(id int) bool { return ranged[id] && !exists[id] })
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: no tasks in %s", domain.ErrNotFound, strings.Join(args, " "))
+	}
+	return ids, nil
+}
+
+func resultIDs(results []usecase.SearchResult) []int {
+	ids := make([]int, len(results))
+	for i, r := range results {
+		ids[i] = r.Task.ID
+	}
+	return ids
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+}
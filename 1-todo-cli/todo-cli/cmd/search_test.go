@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+	"todo-cli/internal/usecase"
+)
+
+func TestHighlight(t *testing.T) {
+	got := highlight("Deploy", []int{0, 1, 4})
+	want := "\x1b[1;33mDe\x1b[0mpl\x1b[1;33mo\x1b[0my"
+	if got != want {
+		t.Fatalf("highlight = %q, want %q", got, want)
+	}
+}
+
+func TestTargetIDs_GapInRange(t *testing.T) {
+	repo := &repository.JSONRepository{Filename: filepath.Join(t.TempDir(), "tasks.json")}
+	taskUsecase = usecase.NewTaskUsecase(repo)
+	for _, name := range []string{"A", "B", "C", "D"} {
+		if _, err := taskUsecase.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := taskUsecase.Delete([]int{2}, usecase.ChildrenRefuse); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args []string
+		want []int
+		err  error
+	}{
+		{args: []string{"1-4"}, want: []int{1, 3, 4}},
+		{args: []string{"1-3,2"}, want: []int{1, 2, 3}},
+		{args: []string{"2"}, want: []int{2}},
+		{args: []string{"2-2"}, err: domain.ErrNotFound},
+		{args: []string{"4-6"}, want: []int{4}},
+	}
+
+	for _, tt := range tests {
+		got, err := targetIDs(tt.args, "finish")
+		if !errors.Is(err, tt.err) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("targetIDs(%q) = %v, %v; want %v, %v", tt.args, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestPickMatches(t *testing.T) {
+	repo := &repository.JSONRepository{Filename: filepath.Join(t.TempDir(), "tasks.json")}
+	taskUsecase = usecase.NewTaskUsecase(repo)
+	for _, name := range []string{"Deploy api", "Deploy web", "Write docs"} {
+		if _, err := taskUsecase.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() { matchQuery, matchYes = "", false }()
+
+	tests := []struct {
+		query, answer string
+		yes           bool
+		want          []int
+		err           error
+	}{
+		{query: "docs", want: []int{3}},
+		{query: "deploy", answer: "2\n", want: []int{2}},
+		{query: "deploy", answer: "a\n", want: []int{1, 2}},
+		{query: "deploy", yes: true, want: []int{1, 2}},
+		{query: "deploy", answer: "\n", want: nil},
+		{query: "deploy", answer: "7\n", err: domain.ErrInvalidInput},
+		{query: "zzz", err: domain.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		matchQuery, matchYes = tt.query, tt.yes
+		stdin = strings.NewReader(tt.answer)
+
+		got, err := pickMatches("finish")
+		if !errors.Is(err, tt.err) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("pickMatches(%q, answer %q) = %v, %v; want %v, %v", tt.query, tt.answer, got, err, tt.want, tt.err)
+		}
+	}
+}
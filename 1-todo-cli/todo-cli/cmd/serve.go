@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"todo-cli/internal/server"
+
+	"github.com/spf13/cobra"
+)
+
+var serveAddr string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the task list as a JSON HTTP API",
+	Long: `Serve the selected task list as a JSON HTTP API until interrupted.
+
+The API has no authentication, so it listens on localhost by default.
+Changes take the same file lock as the other commands, so the CLI
+can be used on the list while it is being served.`,
+	Args: noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ln, err := net.Listen("tcp", serveAddr)
+		if err != nil {
+			return err
+		}
+
+		srv := &http.Server{
+			Handler:           server.New(taskUsecase),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		errc := make(chan error, 1)
+		go func() { errc <- srv.Serve(ln) }()
+
+		fmt.Fprintf(os.Stderr, "Serving list %q on http://%s (Ctrl+C to stop)\n", currentList(), ln.Addr())
+
+		select {
+		case err := <-errc:
+			return err
+		case <-ctx.Done():
+		}
+
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "localhost:7070", "address to listen on, e.g. :7070 for all interfaces")
+	rootCmd.AddCommand(serveCmd)
+}
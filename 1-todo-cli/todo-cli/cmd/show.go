@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a task with its note, links and all other details",
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+
+		task, err := taskUsecase.Get(id)
+		if err != nil {
+			return err
+		}
+		tasks, err := taskUsecase.List()
+		if err != nil {
+			return err
+		}
+
+		return render(task, func() {
+			printTask(task, tasks, time.Now())
+		})
+	},
+}
+
+// printTask prints every detail of a task; all is the list it is in,
+// for the names of related tasks.
+func printTask(t domain.Task, all []domain.Task, now time.Time) {
+	names := make(map[int]domain.Task, len(all))
+	for _, other := range all {
+		names[other.ID] = other
+	}
+	ref := func(id int) string {
+		if other, ok := names[id]; ok {
+			return formatTask(other, now)
+		}
+		return fmt.Sprintf("#%d", id)
+	}
+	stamp := func(tm time.Time) string {
+		return tm.Format("2006-01-02 15:04")
+	}
+	field := func(label, value string) {
+		fmt.Printf("%-11s %s\n", label+":", value)
+	}
+
+	fmt.Printf("#%d %s\n", t.ID, t.Name)
+
+	status := "open"
+	if t.Done {
+		status = "done"
+	}
+	field("Status", status)
+	if t.Priority != domain.PriorityNone {
+		field("Priority", t.Priority.String())
+	}
+	if t.Due != nil {
+		due := dateparse.Format(*t.Due)
+		if t.IsOverdue(now) {
+			due += " (overdue)"
+		}
+		field("Due", due)
+	}
+	if t.Recurrence != nil {
+		field("Repeats", t.Recurrence.String())
+	}
+	if len(t.Tags) > 0 {
+		field("Tags", strings.Join(t.Tags, ", "))
+	}
+	if t.ParentID != 0 {
+		field("Parent", ref(t.ParentID))
+	}
+
+	var children []string
+	done := 0
+	for _, other := range all {
+		if other.ParentID == t.ID {
+			children = append(children, ref(other.ID))
+			if other.Done {
+				done++
+			}
+		}
+	}
+	if len(children) > 0 {
+		field("Subtasks", fmt.Sprintf("%d/%d done", done, len(children)))
+		for _, c := range children {
+			fmt.Printf("%-11s %s\n", "", c)
+		}
+	}
+
+	for i, id := range t.BlockedBy {
+		label := ""
+		if i == 0 {
+			label = "Blocked by:"
+		}
+		fmt.Printf("%-11s %s\n", label, ref(id))
+	}
+
+	if len(t.Time) > 0 {
+		tracked := formatDuration(t.Tracked(now))
+		if t.Running() {
+			tracked += " (running)"
+		}
+		field("Tracked", tracked)
+	}
+	if t.CreatedAt != nil {
+		field("Created", stamp(*t.CreatedAt))
+	}
+	if t.CompletedAt != nil {
+		field("Completed", stamp(*t.CompletedAt))
+	}
+	if t.UpdatedAt != nil {
+		field("Updated", stamp(*t.UpdatedAt))
+	}
+
+	if len(t.Links) > 0 {
+		fmt.Println("\nLinks:")
+		for _, l := range t.Links {
+			fmt.Println("  " + l)
+		}
+	}
+	if t.Note != "" {
+		fmt.Println("\nNote:")
+		for _, line := range strings.Split(t.Note, "\n") {
+			fmt.Println("  " + line)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+}
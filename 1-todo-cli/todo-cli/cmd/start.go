@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start <id>",
+	Short: "Start tracking time on a task",
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+
+		result, err := taskUsecase.Start(id)
+		if err != nil {
+			return err
+		}
+
+		return render(result, func() {
+			if t := result.Stopped; t != nil {
+				fmt.Printf("Stopped #%d %s (%s tracked)\n", t.ID, t.Name, formatDuration(t.Tracked(time.Now())))
+			}
+			fmt.Printf("Started #%d %s\n", result.Started.ID, result.Started.Name)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(startCmd)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+var statsWeeks int
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show completion rate, weekly throughput and open task age",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if statsWeeks < 1 {
+			return fmt.Errorf("%w: --weeks must be at least 1", domain.ErrInvalidInput)
+		}
+
+		stats, err := taskUsecase.Stats(statsWeeks)
+		if err != nil {
+			return err
+		}
+
+		return render(stats, func() {
+			fmt.Printf("Tasks:            %d (%d open, %d done, %d overdue)\n", stats.Total, stats.Open, stats.Done, stats.Overdue)
+			fmt.Printf("Completion rate:  %.0f%%\n", stats.CompletionRate*100)
+			fmt.Printf("Average open age: %.1f days\n", stats.AverageOpenAge)
+			fmt.Println("Completed per week:")
+
+			most := 0
+			for _, w := range stats.Weekly {
+				most = max(most, w.Done)
+			}
+			for _, w := range stats.Weekly {
+				bar := ""
+				if most > 0 {
+					bar = strings.Repeat("█", w.Done*20/most)
+				}
+				line := fmt.Sprintf("  %s (%s) %3d %s", w.Week, dateparse.Format(w.Start), w.Done, bar)
+				fmt.Println(strings.TrimRight(line, " "))
+			}
+		})
+	},
+}
+
+func init() {
+	statsCmd.Flags().IntVar(&statsWeeks, "weeks", 8, "number of weeks of throughput to show")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		task, err := taskUsecase.Stop()
+		if err != nil {
+			return err
+		}
+
+		return render(task, func() {
+			last := task.Time[len(task.Time)-1]
+			fmt.Printf("Stopped #%d %s after %s (%s tracked)\n",
+				task.ID, task.Name, formatDuration(last.End.Sub(last.Start)), formatDuration(task.Tracked(*last.End)))
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stopCmd)
+}
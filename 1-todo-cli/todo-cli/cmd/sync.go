@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"todo-cli/internal/config"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+	"todo-cli/internal/usecase"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncInteractive bool
+	syncDryRun      bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <other-file>",
+	Short: "Merge the list with another copy of it, e.g. in a synced folder",
+	Long: `Merge the list with another copy of it and save the result to both.
+
+Changes made on either side since the last sync between the two are
+combined field by field. When both sides changed the same field, the
+more recently updated version wins, and an edited task wins over its
+deletion; --interactive asks instead. The other file is created on the
+first sync and is stored encrypted if the list is.`,
+	Args: exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+
+		same, err := samePath(path, dataPath)
+		if err != nil {
+			return err
+		}
+		if same {
+			return fmt.Errorf("%w: %s is the list itself", domain.ErrInvalidInput, path)
+		}
+
+		basePath, err := config.SyncBasePath(dataPath, path)
+		if err != nil {
+			return err
+		}
+
+		var resolve usecase.Resolver
+		if syncInteractive {
+			resolve = askResolver(bufio.NewReader(stdin))
+		}
+
+		result, err := taskUsecase.Sync(syncRepository(path), syncRepository(basePath), resolve, syncDryRun)
+		if err != nil {
+			return err
+		}
+
+		return render(result, func() {
+			now := time.Now()
+			for _, c := range result.Conflicts {
+				fmt.Printf("conflict on #%d (%s): kept %s\n", c.ID, conflictSummary(c), c.Kept)
+			}
+			for _, old := range slices.Sorted(maps.Keys(result.Renumbered)) {
+				fmt.Printf("#%d from %s is now #%d\n", old, path, result.Renumbered[old])
+			}
+			for _, c := range result.Local {
+				fmt.Println("local: ", describeChange(c, now))
+			}
+			for _, c := range result.Other {
+				fmt.Println("other: ", describeChange(c, now))
+			}
+
+			verb := "Synced"
+			if syncDryRun {
+				verb = "Dry run:"
+			}
+			fmt.Printf("%s %d changes to the list, %d to %s, %d conflicts\n",
+				verb, len(result.Local), len(result.Other), path, len(result.Conflicts))
+		})
+	},
+}
+
+// syncRepository opens a file taking part in a sync the same way as
+// the list itself, encrypted or not.
+func syncRepository(path string) repository.TaskRepository {
+	if listCipher != nil {
+		return &repository.EncryptedRepository{Filename: path, Cipher: listCipher}
+	}
+	return &repository.JSONRepository{Filename: path}
+}
+
+func samePath(a, b string) (bool, error) {
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+	absB, err := filepath.Abs(b)
+	if err != nil {
+		return false, err
+	}
+	return absA == absB, nil
+}
+
+// describeChange renders a change as e.g. "+ [ ] #4 Write docs".
+func describeChange(c domain.Change, now time.Time) string {
+	mark := "~"
+	switch {
+	case c.Before == nil:
+		mark = "+"
+	case c.After == nil:
+		mark = "-"
+	}
+	return mark + " " + formatTask(c.Task(), now)
+}
+
+func conflictSummary(c usecase.Conflict) string {
+	switch {
+	case c.Local == nil:
+		return "deleted locally, changed in the other file"
+	case c.Other == nil:
+		return "changed locally, deleted in the other file"
+	}
+	return "both changed " + strings.Join(c.Fields, ", ")
+}
+
+// askResolver settles each conflict by asking on stderr which version to keep.
+func askResolver(in *bufio.Reader) usecase.Resolver {
+	return func(c usecase.Conflict) (usecase.Side, error) {
+		now := time.Now()
+		show := func(label string, t *domain.Task) {
+			if t == nil {
+				fmt.Fprintf(os.Stderr, "  %s: (deleted)\n", label)
+				return
+			}
+			fmt.Fprintf(os.Stderr, "  %s: %s\n", label, formatTask(*t, now))
+		}
+
+		fmt.Fprintf(os.Stderr, "Conflict on #%d, %s:\n", c.ID, conflictSummary(c))
+		show("local", c.Local)
+		show("other", c.Other)
+
+		for {
+			fmt.Fprint(os.Stderr, "Keep which version? [l]ocal/[o]ther: ")
+			answer, err := in.ReadString('\n')
+			if err != nil && err != io.EOF {
+				return 0, err
+			}
+
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "l", "local":
+				return usecase.SideLocal, nil
+			case "o", "other":
+				return usecase.SideOther, nil
+			}
+			if err == io.EOF {
+				return 0, fmt.Errorf("%w: no answer for conflict on #%d", domain.ErrInvalidInput, c.ID)
+			}
+		}
+	}
+}
+
+func init() {
+	syncCmd.Flags().BoolVarP(&syncInteractive, "interactive", "i", false, "ask which version to keep for each conflict")
+	syncCmd.Flags().BoolVarP(&syncDryRun, "dry-run", "n", false, "show what would change without saving")
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"todo-cli/internal/tui"
+
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and edit tasks interactively",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return tui.Run(taskUsecase, currentList())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var unblockOn int
+
+var unblockCmd = &cobra.Command{
+	Use:   "unblock <id> --on <other>",
+	Short: "Remove a dependency added with block",
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+
+		task, err := taskUsecase.Unblock(id, unblockOn)
+		if err != nil {
+			return err
+		}
+
+		return render(task, func() {
+			fmt.Printf("#%d no longer waits for #%d.\n", id, unblockOn)
+		})
+	},
+}
+
+func init() {
+	unblockCmd.Flags().IntVar(&unblockOn, "on", 0, "ID of the task it waits for")
+	unblockCmd.MarkFlagRequired("on")
+	rootCmd.AddCommand(unblockCmd)
+}
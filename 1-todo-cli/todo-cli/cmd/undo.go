@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last change",
+	Args:  noArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		op, err := taskUsecase.Undo()
+		if err != nil {
+			return err
+		}
+
+		return render(op, func() {
+			fmt.Println("Undone:", describeOperation(op))
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+}
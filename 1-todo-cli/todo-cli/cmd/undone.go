@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var undoneCmd = &cobra.Command{
+	Use:   "undone <id>...",
+	Short: "Mark completed tasks as not done",
+	Args:  targetArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := targetIDs(args, "reopen")
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+			return nil
+		}
+
+		reopened, err := taskUsecase.Reopen(ids)
+		if err != nil {
+			return err
+		}
+
+		return render(reopened, func() {
+			fmt.Printf("%d task(s) reopened.\n", len(reopened))
+		})
+	},
+}
+
+func init() {
+	addMatchFlags(undoneCmd)
+	rootCmd.AddCommand(undoneCmd)
+}
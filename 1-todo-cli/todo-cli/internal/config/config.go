@@ -0,0 +1,186 @@
+// Package config loads the user configuration and resolves
+// where each named task list is stored.
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+const (
+	appName     = "todo"
+	DefaultList = "tasks"
+
+	dataExt    = ".json"
+	journalExt = ".journal.json"
+	archiveExt = ".archive.json"
+	syncInfix  = ".sync-"
+)
+
+var listName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Config is read from config.json in the XDG config directory,
+// e.g. ~/.config/todo/config.json.
+type Config struct {
+	// DataDir holds one <list>.json file per named list.
+	// Relative paths are resolved against the config file's directory.
+	DataDir string `json:"data_dir"`
+	// DefaultList is used when --list is not given.
+	DefaultList string `json:"default_list"`
+}
+
+// Path returns the default location of the config file.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName, "config.json"), nil
+}
+
+// Load reads the config file at path. A missing file yields the defaults.
+func Load(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return cfg, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+
+	if cfg.DefaultList == "" {
+		cfg.DefaultList = DefaultList
+	}
+
+	if cfg.DataDir == "" {
+		if cfg.DataDir, err = defaultDataDir(); err != nil {
+			return cfg, err
+		}
+	}
+	cfg.DataDir = expandHome(cfg.DataDir)
+	if !filepath.IsAbs(cfg.DataDir) {
+		cfg.DataDir = filepath.Join(filepath.Dir(path), cfg.DataDir)
+	}
+
+	return cfg, nil
+}
+
+// ListPath returns the data file of a named list.
+func (c Config) ListPath(name string) (string, error) {
+	if !listName.MatchString(name) {
+		return "", fmt.Errorf("invalid list name %q (use letters, digits, - and _)", name)
+	}
+	return filepath.Join(c.DataDir, name+dataExt), nil
+}
+
+// JournalPath returns the undo journal stored next to a list's data file.
+func JournalPath(dataPath string) string {
+	return strings.TrimSuffix(dataPath, dataExt) + journalExt
+}
+
+// ArchivePath returns the archive of completed tasks stored next to
+// a list's data file.
+func ArchivePath(dataPath string) string {
+	return strings.TrimSuffix(dataPath, dataExt) + archiveExt
+}
+
+// SyncBasePath returns where the state of a list after its last sync
+// with the file at otherPath is kept. Each synced file has its own base,
+// named after a hash of its absolute path.
+func SyncBasePath(dataPath, otherPath string) (string, error) {
+	abs, err := filepath.Abs(otherPath)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return fmt.Sprintf("%s%s%x%s", strings.TrimSuffix(dataPath, dataExt), syncInfix, sum[:8], dataExt), nil
+}
+
+// SyncBasePaths returns the sync bases present for a list.
+func SyncBasePaths(dataPath string) ([]string, error) {
+	dir := filepath.Dir(dataPath)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	prefix := strings.TrimSuffix(filepath.Base(dataPath), dataExt) + syncInfix
+	var paths []string
+	for _, e := range entries {
+		if name := e.Name(); !e.IsDir() && strings.HasPrefix(name, prefix) && strings.HasSuffix(name, dataExt) {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	return paths, nil
+}
+
+// Lists returns the names of the lists present in the data directory.
+func (c Config) Lists() ([]string, error) {
+	entries, err := os.ReadDir(c.DataDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, dataExt) || strings.HasSuffix(name, journalExt) || strings.HasSuffix(name, archiveExt) || strings.Contains(name, syncInfix) {
+			continue
+		}
+		if name = strings.TrimSuffix(name, dataExt); listName.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// defaultDataDir follows the XDG base directory spec on Unix.
+func defaultDataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, appName), nil
+	}
+
+	if runtime.GOOS == "windows" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, appName, "data"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", appName), nil
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
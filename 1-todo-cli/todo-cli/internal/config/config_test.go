@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.DataDir != "/xdg/data/todo" {
+		t.Fatalf("unexpected data dir: %s", cfg.DataDir)
+	}
+	if cfg.DefaultList != DefaultList {
+		t.Fatalf("unexpected default list: %s", cfg.DefaultList)
+	}
+}
+
+func TestLoad_RelativeDataDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"data_dir": "lists", "default_list": "work"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.DataDir != filepath.Join(dir, "lists") || cfg.DefaultList != "work" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestListPathAndLists(t *testing.T) {
+	cfg := Config{DataDir: t.TempDir()}
+
+	for _, name := range []string{"work", "personal"} {
+		path, err := cfg.ListPath(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(JournalPath(path), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		base, err := SyncBasePath(path, "/shared/tasks.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(base, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lists, err := cfg.Lists()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(lists, []string{"personal", "work"}) {
+		t.Fatalf("unexpected lists: %v", lists)
+	}
+
+	work, _ := cfg.ListPath("work")
+	bases, err := SyncBasePaths(work)
+	if err != nil || len(bases) != 1 {
+		t.Fatalf("expected one sync base, got %v (%v)", bases, err)
+	}
+
+	if _, err := cfg.ListPath("../etc"); err == nil {
+		t.Fatal("expected error for path-like list name")
+	}
+}
@@ -0,0 +1,132 @@
+// Package dateparse turns user-supplied due dates into calendar days.
+// It accepts ISO dates (2026-11-01) and a small set of natural phrases:
+// today, tomorrow, yesterday, weekday names ("fri", "next friday"),
+// "next week", "next month" and relative offsets ("in 3 days", "+2w").
+package dateparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const isoLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Parse resolves s relative to now and returns midnight of that day
+// in now's location.
+func Parse(s string, now time.Time) (time.Time, error) {
+	in := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	today := Day(now)
+
+	if t, err := time.ParseInLocation(isoLayout, in, now.Location()); err == nil {
+		return t, nil
+	}
+
+	switch in {
+	case "today":
+		return today, nil
+	case "tomorrow", "tmr":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "next week":
+		return today.AddDate(0, 0, 7), nil
+	case "next month":
+		return today.AddDate(0, 1, 0), nil
+	}
+
+	// "fri", "next fri" and "this fri" all mean the first such
+	// weekday strictly after today.
+	day := strings.TrimPrefix(strings.TrimPrefix(in, "next "), "this ")
+	if wd, ok := weekdays[day]; ok {
+		diff := (int(wd) - int(today.Weekday()) + 7) % 7
+		if diff == 0 {
+			diff = 7
+		}
+		return today.AddDate(0, 0, diff), nil
+	}
+
+	if t, ok := parseOffset(in, today); ok {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse date %q (try 2026-11-01, tomorrow or next fri)", s)
+}
+
+// Since resolves the start of a reporting period: a span back from today
+// such as "7d", "2w" or "3m", or any date Parse accepts. It returns
+// midnight of that day.
+func Since(s string, now time.Time) (time.Time, error) {
+	in := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "-"))
+	today := Day(now)
+
+	if len(in) > 1 {
+		if n, err := strconv.Atoi(in[:len(in)-1]); err == nil && n >= 0 {
+			switch in[len(in)-1] {
+			case 'd':
+				return today.AddDate(0, 0, -n), nil
+			case 'w':
+				return today.AddDate(0, 0, -7*n), nil
+			case 'm':
+				return today.AddDate(0, -n, 0), nil
+			}
+		}
+	}
+
+	return Parse(s, now)
+}
+
+// Day truncates t to midnight in its own location.
+func Day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Format renders a due date the way Parse accepts it back.
+func Format(t time.Time) string {
+	return t.Format(isoLayout)
+}
+
+// parseOffset handles "in 3 days", "in 2 weeks", "+3d" and "+2w".
+func parseOffset(in string, today time.Time) (time.Time, bool) {
+	var num, unit string
+
+	switch {
+	case strings.HasPrefix(in, "in "):
+		parts := strings.Fields(strings.TrimPrefix(in, "in "))
+		if len(parts) != 2 {
+			return time.Time{}, false
+		}
+		num, unit = parts[0], parts[1]
+	case strings.HasPrefix(in, "+") && len(in) > 2:
+		num, unit = in[1:len(in)-1], in[len(in)-1:]
+	default:
+		return time.Time{}, false
+	}
+
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+
+	switch strings.TrimSuffix(unit, "s") {
+	case "d", "day":
+		return today.AddDate(0, 0, n), true
+	case "w", "week":
+		return today.AddDate(0, 0, 7*n), true
+	case "m", "month":
+		return today.AddDate(0, n, 0), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,74 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"2026-11-01", "2026-11-01"},
+		{"today", "2026-10-14"},
+		{"Tomorrow", "2026-10-15"},
+		{"fri", "2026-10-16"},
+		{"next fri", "2026-10-16"},
+		{"next wednesday", "2026-10-21"},
+		{"next week", "2026-10-21"},
+		{"next month", "2026-11-14"},
+		{"in 3 days", "2026-10-17"},
+		{"in 1 week", "2026-10-21"},
+		{"+2w", "2026-10-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if Format(got) != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, Format(got))
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, in := range []string{"", "someday", "in x days", "2026-13-01"} {
+		if _, err := Parse(in, time.Now()); err == nil {
+			t.Fatalf("expected error for %q", in)
+		}
+	}
+}
+
+func TestSince(t *testing.T) {
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"7d", "2026-10-07"},
+		{"-2w", "2026-09-30"},
+		{"1m", "2026-09-14"},
+		{"0d", "2026-10-14"},
+		{"yesterday", "2026-10-13"},
+		{"2026-10-01", "2026-10-01"},
+	}
+
+	for _, tt := range tests {
+		got, err := Since(tt.in, now)
+		if err != nil {
+			t.Fatalf("Since(%q): unexpected error: %v", tt.in, err)
+		}
+		if Format(got) != tt.want {
+			t.Fatalf("Since(%q): expected %s, got %s", tt.in, tt.want, Format(got))
+		}
+	}
+}
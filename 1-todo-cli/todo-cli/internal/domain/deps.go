@@ -0,0 +1,46 @@
+package domain
+
+// OpenBlockers maps the ID of each task that waits for open tasks
+// to the IDs of those tasks. Done tasks and unknown IDs do not block.
+func OpenBlockers(tasks []Task) map[int][]int {
+	open := make(map[int]bool, len(tasks))
+	for _, t := range tasks {
+		open[t.ID] = !t.Done
+	}
+
+	blockers := make(map[int][]int)
+	for _, t := range tasks {
+		for _, id := range t.BlockedBy {
+			if open[id] {
+				blockers[t.ID] = append(blockers[t.ID], id)
+			}
+		}
+	}
+	return blockers
+}
+
+// DependsOn reports whether task id waits for task on,
+// directly or through other tasks.
+func DependsOn(tasks []Task, id, on int) bool {
+	blockedBy := make(map[int][]int, len(tasks))
+	for _, t := range tasks {
+		blockedBy[t.ID] = t.BlockedBy
+	}
+
+	seen := map[int]bool{}
+	queue := []int{id}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for _, b := range blockedBy[next] {
+			if b == on {
+				return true
+			}
+			if !seen[b] {
+				seen[b] = true
+				queue = append(queue, b)
+			}
+		}
+	}
+	return false
+}
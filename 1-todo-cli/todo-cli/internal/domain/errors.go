@@ -0,0 +1,18 @@
+package domain
+
+import "errors"
+
+// Domain-level error classes.
+// Errors returned by the usecase wrap one of these sentinels
+// so callers can tell what kind of failure happened with errors.Is.
+
+var (
+	// ErrNotFound indicates the task does not exist.
+	ErrNotFound = errors.New("task not found")
+
+	// ErrInvalidInput indicates validation failure.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrConflict indicates the request clashes with the current state.
+	ErrConflict = errors.New("conflict")
+)
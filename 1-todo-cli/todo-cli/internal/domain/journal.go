@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Journal is the history of mutations made to a task list.
+// Operations before Cursor are applied; those from Cursor on
+// have been undone and can be redone.
+type Journal struct {
+	Cursor     int         `json:"cursor"`
+	Operations []Operation `json:"operations"`
+}
+
+// Operation is one mutating command and the task changes it made.
+// OrderBefore and OrderAfter hold the task IDs in list order and are
+// only recorded when the operation rearranged existing tasks.
+type Operation struct {
+	Name        string    `json:"name"`
+	Time        time.Time `json:"time"`
+	Changes     []Change  `json:"changes"`
+	OrderBefore []int     `json:"order_before,omitempty"`
+	OrderAfter  []int     `json:"order_after,omitempty"`
+}
+
+// Change records a single task before and after an operation.
+// Before is nil for added tasks and After is nil for deleted ones.
+// Index is the task's position in the list where it exists:
+// the old list for deletions, the new list otherwise.
+type Change struct {
+	Before *Task `json:"before,omitempty"`
+	After  *Task `json:"after,omitempty"`
+	Index  int   `json:"index"`
+}
+
+// Task returns whichever side of the change is present,
+// preferring the state after the operation.
+func (c Change) Task() Task {
+	if c.After != nil {
+		return *c.After
+	}
+	return *c.Before
+}
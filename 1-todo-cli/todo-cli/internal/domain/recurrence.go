@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurrenceKind is the shape of a repeat rule.
+type RecurrenceKind string
+
+const (
+	RecurDaily   RecurrenceKind = "daily"
+	RecurWeekly  RecurrenceKind = "weekly"
+	RecurMonthly RecurrenceKind = "monthly"
+	RecurEvery   RecurrenceKind = "every"
+)
+
+// Recurrence says when a completed task comes back.
+// Weekdays applies to weekly rules, Day (1-31) to monthly rules
+// and Interval (in days) to every-N-days rules.
+type Recurrence struct {
+	Kind     RecurrenceKind
+	Weekdays []time.Weekday
+	Day      int
+	Interval int
+}
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseRecurrence accepts "daily", "weekly", "weekly:mon,thu", "monthly",
+// "monthly:15", "every:3d" and the spelled-out forms "weekly on mon,thu",
+// "monthly on 15" and "every 3 days".
+func ParseRecurrence(s string) (Recurrence, error) {
+	in := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	in = strings.Replace(in, " on ", ":", 1)
+	if rest, ok := strings.CutPrefix(in, "every "); ok {
+		in = "every:" + strings.TrimSuffix(strings.TrimSuffix(rest, " days"), " day")
+	}
+
+	kind, arg, _ := strings.Cut(in, ":")
+	r := Recurrence{Kind: RecurrenceKind(kind)}
+
+	switch r.Kind {
+	case RecurDaily:
+		if arg == "" {
+			return r, nil
+		}
+	case RecurWeekly:
+		if arg == "" {
+			return r, nil
+		}
+		for _, name := range strings.Split(arg, ",") {
+			wd, ok := parseWeekday(strings.TrimSpace(name))
+			if !ok {
+				return Recurrence{}, fmt.Errorf("%w: weekday %q in %q", ErrInvalidInput, name, s)
+			}
+			r.Weekdays = append(r.Weekdays, wd)
+		}
+		return r, nil
+	case RecurMonthly:
+		if arg == "" {
+			return r, nil
+		}
+		day, err := strconv.Atoi(arg)
+		if err == nil && day >= 1 && day <= 31 {
+			r.Day = day
+			return r, nil
+		}
+	case RecurEvery:
+		n, err := strconv.Atoi(strings.TrimSuffix(arg, "d"))
+		if err == nil && n > 0 {
+			r.Interval = n
+			return r, nil
+		}
+	}
+
+	return Recurrence{}, fmt.Errorf("%w: repeat rule %q (try daily, weekly:mon,thu, monthly:15 or every:3d)", ErrInvalidInput, s)
+}
+
+// parseWeekday accepts any prefix of a weekday name of at least three letters.
+func parseWeekday(s string) (time.Weekday, bool) {
+	if len(s) < 3 {
+		return 0, false
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if strings.HasPrefix(strings.ToLower(wd.String()), s) {
+			return wd, true
+		}
+	}
+	return 0, false
+}
+
+// String returns the canonical form accepted by ParseRecurrence.
+func (r Recurrence) String() string {
+	switch r.Kind {
+	case RecurWeekly:
+		if len(r.Weekdays) == 0 {
+			return "weekly"
+		}
+		names := make([]string, len(r.Weekdays))
+		for i, wd := range r.Weekdays {
+			names[i] = weekdayNames[wd]
+		}
+		return "weekly:" + strings.Join(names, ",")
+	case RecurMonthly:
+		if r.Day == 0 {
+			return "monthly"
+		}
+		return fmt.Sprintf("monthly:%d", r.Day)
+	case RecurEvery:
+		return fmt.Sprintf("every:%dd", r.Interval)
+	}
+	return string(r.Kind)
+}
+
+func (r Recurrence) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Recurrence) UnmarshalText(text []byte) error {
+	parsed, err := ParseRecurrence(string(text))
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Anchor fills in the weekday or day of month a bare "weekly" or
+// "monthly" rule repeats on, taken from the first due date.
+func (r Recurrence) Anchor(due time.Time) Recurrence {
+	switch {
+	case r.Kind == RecurWeekly && len(r.Weekdays) == 0:
+		r.Weekdays = []time.Weekday{due.Weekday()}
+	case r.Kind == RecurMonthly && r.Day == 0:
+		r.Day = due.Day()
+	}
+	return r
+}
+
+// First returns the first occurrence on or after from, at midnight.
+// Interval rules start on from itself.
+func (r Recurrence) First(from time.Time) time.Time {
+	if r.Kind == RecurEvery {
+		return r.Next(from.AddDate(0, 0, -r.Interval))
+	}
+	return r.Next(from.AddDate(0, 0, -1))
+}
+
+// Next returns the first occurrence strictly after from, at midnight.
+// Monthly days past the end of a month fall on its last day.
+func (r Recurrence) Next(from time.Time) time.Time {
+	y, m, d := from.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, from.Location())
+
+	switch r.Kind {
+	case RecurWeekly:
+		if len(r.Weekdays) == 0 {
+			return day.AddDate(0, 0, 7)
+		}
+		for i := 1; i <= 7; i++ {
+			next := day.AddDate(0, 0, i)
+			for _, wd := range r.Weekdays {
+				if next.Weekday() == wd {
+					return next
+				}
+			}
+		}
+	case RecurMonthly:
+		want := r.Day
+		if want == 0 {
+			want = d
+		}
+		for i := 0; i <= 1; i++ {
+			next := monthDay(y, m+time.Month(i), want, from.Location())
+			if next.After(day) {
+				return next
+			}
+		}
+		return monthDay(y, m+2, want, from.Location())
+	case RecurEvery:
+		return day.AddDate(0, 0, max(r.Interval, 1))
+	}
+
+	return day.AddDate(0, 0, 1)
+}
+
+// monthDay returns day d of the given month, clamped to its last day.
+func monthDay(y int, m time.Month, d int, loc *time.Location) time.Time {
+	last := time.Date(y, m+1, 0, 0, 0, 0, 0, loc).Day()
+	return time.Date(y, m, min(d, last), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"daily", "daily"},
+		{"Weekly", "weekly"},
+		{"weekly:mon,thu", "weekly:mon,thu"},
+		{"weekly on monday", "weekly:mon"},
+		{"monthly:15", "monthly:15"},
+		{"monthly on 31", "monthly:31"},
+		{"every:3d", "every:3d"},
+		{"every 10 days", "every:10d"},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.in, err)
+		}
+		if r.String() != tt.want {
+			t.Fatalf("%q: expected %s, got %s", tt.in, tt.want, r.String())
+		}
+	}
+
+	for _, in := range []string{"", "hourly", "weekly:funday", "monthly:32", "every:0d"} {
+		if _, err := ParseRecurrence(in); err == nil {
+			t.Fatalf("expected error for %q", in)
+		}
+	}
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	mustParse := func(s string) Recurrence {
+		r, err := ParseRecurrence(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	// 2026-10-14 is a Wednesday.
+	tests := []struct {
+		rule string
+		from time.Time
+		want time.Time
+	}{
+		{"daily", date(2026, 10, 14), date(2026, 10, 15)},
+		{"every:3d", date(2026, 10, 14), date(2026, 10, 17)},
+		{"weekly:mon,thu", date(2026, 10, 14), date(2026, 10, 15)},
+		{"weekly:mon,thu", date(2026, 10, 15), date(2026, 10, 19)},
+		{"weekly:wed", date(2026, 10, 14), date(2026, 10, 21)},
+		{"monthly:15", date(2026, 10, 14), date(2026, 10, 15)},
+		{"monthly:15", date(2026, 10, 15), date(2026, 11, 15)},
+		{"monthly:31", date(2026, 1, 31), date(2026, 2, 28)},
+		{"monthly:31", date(2026, 2, 28), date(2026, 3, 31)},
+	}
+
+	for _, tt := range tests {
+		got := mustParse(tt.rule).Next(tt.from)
+		if !got.Equal(tt.want) {
+			t.Fatalf("%s from %s: expected %s, got %s", tt.rule,
+				tt.from.Format("2006-01-02"), tt.want.Format("2006-01-02"), got.Format("2006-01-02"))
+		}
+	}
+}
@@ -1,7 +1,109 @@
 package domain
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Task is a single todo item. ID is permanent:
+// it is never renumbered or reused once issued.
+// ParentID is zero for top-level tasks. A recurring task carries its
+// Recurrence until completed; the rule then moves to the next occurrence.
+// Tags starting with '@' are contexts in todo.txt terms;
+// all other tags are projects. Time lists the intervals tracked
+// against the task, at most the last of them still running.
+// BlockedBy lists the IDs of tasks that must be done first.
+// UpdatedAt is when the task last changed; sync uses it to settle
+// conflicting edits. Note is free text of any length; Links are URLs or
+// file paths that belong with the task.
 type Task struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-	Done bool   `json:"done"`
+	ID          int         `json:"id"`
+	ParentID    int         `json:"parent_id,omitempty"`
+	Name        string      `json:"name"`
+	Done        bool        `json:"done"`
+	Due         *time.Time  `json:"due,omitempty"`
+	Priority    Priority    `json:"priority,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+	CreatedAt   *time.Time  `json:"created_at,omitempty"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+	UpdatedAt   *time.Time  `json:"updated_at,omitempty"`
+	Recurrence  *Recurrence `json:"recurrence,omitempty"`
+	Time        []Interval  `json:"time,omitempty"`
+	BlockedBy   []int       `json:"blocked_by,omitempty"`
+	Note        string      `json:"note,omitempty"`
+	Links       []string    `json:"links,omitempty"`
+}
+
+// HasTag reports whether the task carries the given tag (case-insensitive).
+func (t Task) HasTag(tag string) bool {
+	for _, tg := range t.Tags {
+		if strings.EqualFold(tg, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOverdue reports whether an open task's due date lies before today.
+func (t Task) IsOverdue(now time.Time) bool {
+	if t.Done || t.Due == nil {
+		return false
+	}
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	return t.Due.Before(today)
+}
+
+// Priority ranks tasks; the zero value means no priority was set.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+var priorityNames = map[Priority]string{
+	PriorityNone:   "none",
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+}
+
+// ParsePriority accepts the priority names and their first letter.
+func ParsePriority(s string) (Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none":
+		return PriorityNone, nil
+	case "l", "low":
+		return PriorityLow, nil
+	case "m", "med", "medium":
+		return PriorityMedium, nil
+	case "h", "high":
+		return PriorityHigh, nil
+	}
+	return PriorityNone, fmt.Errorf("%w: priority %q (want low, medium or high)", ErrInvalidInput, s)
+}
+
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// MarshalText stores priorities by name so the data file stays readable.
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Priority) UnmarshalText(text []byte) error {
+	parsed, err := ParsePriority(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
 }
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// Interval is a span of time tracked against a task.
+// End is nil while the timer is running.
+type Interval struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+// Overlap returns how much of the interval falls between from and to,
+// counting a running interval as ending at to.
+func (iv Interval) Overlap(from, to time.Time) time.Duration {
+	start, end := iv.Start, to
+	if iv.End != nil && iv.End.Before(to) {
+		end = *iv.End
+	}
+	if start.Before(from) {
+		start = from
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// Running reports whether a timer is running on the task.
+func (t Task) Running() bool {
+	return len(t.Time) > 0 && t.Time[len(t.Time)-1].End == nil
+}
+
+// Tracked is the total time tracked on the task, up to now for a running timer.
+func (t Task) Tracked(now time.Time) time.Duration {
+	var total time.Duration
+	for _, iv := range t.Time {
+		total += iv.Overlap(iv.Start, now)
+	}
+	return total
+}
+
+// StartTimer opens a new interval at now unless one is already running.
+func (t *Task) StartTimer(now time.Time) bool {
+	if t.Running() {
+		return false
+	}
+	t.Time = append(t.Time, Interval{Start: now})
+	return true
+}
+
+// StopTimer closes the running interval at now, if there is one.
+func (t *Task) StopTimer(now time.Time) bool {
+	if !t.Running() {
+		return false
+	}
+	t.Time[len(t.Time)-1].End = &now
+	return true
+}
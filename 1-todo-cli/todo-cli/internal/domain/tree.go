@@ -0,0 +1,89 @@
+package domain
+
+// TaskNode is a task with its subtasks.
+// DoneChildren and TotalChildren count all direct subtasks,
+// including those left out of the tree by a filter.
+// Blockers are the open tasks this one waits for.
+type TaskNode struct {
+	Task          Task
+	Children      []*TaskNode
+	DoneChildren  int
+	TotalChildren int
+	Blockers      []int
+}
+
+// BuildTree arranges tasks under their parents, keeping the given order
+// among siblings. Tasks whose parent is not in the list, or whose
+// parent chain loops back to them, become roots.
+// all is the complete task list used for the completion counts
+// and blockers.
+func BuildTree(tasks, all []Task) []*TaskNode {
+	blockers := OpenBlockers(all)
+	nodes := make(map[int]*TaskNode, len(tasks))
+	for _, t := range tasks {
+		nodes[t.ID] = &TaskNode{Task: t, Blockers: blockers[t.ID]}
+	}
+
+	for _, t := range all {
+		if parent, ok := nodes[t.ParentID]; ok && t.ParentID != 0 {
+			parent.TotalChildren++
+			if t.Done {
+				parent.DoneChildren++
+			}
+		}
+	}
+
+	var roots []*TaskNode
+	for _, t := range tasks {
+		node := nodes[t.ID]
+		if parent, ok := nodes[t.ParentID]; ok && t.ParentID != 0 && !inLoop(nodes, t.ID) {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// inLoop reports whether the parent chain of id leads back to it.
+func inLoop(nodes map[int]*TaskNode, id int) bool {
+	seen := map[int]bool{}
+	for p := nodes[id].Task.ParentID; p != 0 && !seen[p]; {
+		if p == id {
+			return true
+		}
+		seen[p] = true
+		parent, ok := nodes[p]
+		if !ok {
+			break
+		}
+		p = parent.Task.ParentID
+	}
+	return false
+}
+
+// Descendants returns the IDs of all subtasks below id, at any depth.
+func Descendants(tasks []Task, id int) []int {
+	children := make(map[int][]int)
+	for _, t := range tasks {
+		if t.ParentID != 0 {
+			children[t.ParentID] = append(children[t.ParentID], t.ID)
+		}
+	}
+
+	var result []int
+	seen := map[int]bool{id: true}
+	queue := []int{id}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for _, c := range children[next] {
+			if !seen[c] {
+				seen[c] = true
+				result = append(result, c)
+				queue = append(queue, c)
+			}
+		}
+	}
+	return result
+}
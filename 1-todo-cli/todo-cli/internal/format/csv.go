@@ -0,0 +1,168 @@
+package format
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todo-cli/internal/domain"
+)
+
+// CSV writes one row per task under a header row.
+// Tags are joined with ';'. On import only the name column is required
+// and columns are matched by header, in any order.
+type CSV struct{}
+
+var csvHeader = []string{"id", "name", "done", "priority", "due", "tags", "created_at", "completed_at"}
+
+func (CSV) Encode(w io.Writer, tasks []domain.Task) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		priority := ""
+		if t.Priority != domain.PriorityNone {
+			priority = t.Priority.String()
+		}
+
+		row := []string{
+			strconv.Itoa(t.ID),
+			t.Name,
+			strconv.FormatBool(t.Done),
+			priority,
+			formatDate(t.Due, dateLayout),
+			strings.Join(t.Tags, ";"),
+			formatDate(t.CreatedAt, time.RFC3339),
+			formatDate(t.CompletedAt, time.RFC3339),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (CSV) Decode(r io.Reader) ([]domain.Task, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	col := make(map[string]int)
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := col["name"]; !ok {
+		return nil, fmt.Errorf("csv header has no name column")
+	}
+
+	var tasks []domain.Task
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		t, err := parseCSVRow(row, col)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, nil
+}
+
+func parseCSVRow(row []string, col map[string]int) (domain.Task, error) {
+	get := func(name string) string {
+		i, ok := col[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	t := domain.Task{Name: get("name")}
+	if t.Name == "" {
+		return t, fmt.Errorf("task has no name")
+	}
+
+	if v := get("id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return t, fmt.Errorf("invalid id %q", v)
+		}
+		t.ID = id
+	}
+
+	if v := get("done"); v != "" {
+		done, err := strconv.ParseBool(v)
+		if err != nil {
+			return t, fmt.Errorf("invalid done value %q", v)
+		}
+		t.Done = done
+	}
+
+	p, err := domain.ParsePriority(get("priority"))
+	if err != nil {
+		return t, err
+	}
+	t.Priority = p
+
+	if v := get("tags"); v != "" {
+		for _, tag := range strings.Split(v, ";") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				t.Tags = append(t.Tags, tag)
+			}
+		}
+	}
+
+	if t.Due, err = parseCSVDate(get("due"), dateLayout); err != nil {
+		return t, err
+	}
+	if t.CreatedAt, err = parseCSVDate(get("created_at"), time.RFC3339); err != nil {
+		return t, err
+	}
+	if t.CompletedAt, err = parseCSVDate(get("completed_at"), time.RFC3339); err != nil {
+		return t, err
+	}
+
+	return t, nil
+}
+
+func formatDate(t *time.Time, layout string) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+// parseCSVDate accepts the given layout or a plain date.
+func parseCSVDate(v, layout string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	for _, l := range []string{layout, dateLayout} {
+		if t, err := time.ParseInLocation(l, v, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid date %q", v)
+}
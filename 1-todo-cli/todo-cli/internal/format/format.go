@@ -0,0 +1,61 @@
+// Package format converts task lists to and from the file formats
+// used by other tools: todo.txt, CSV, Markdown checklists and JSON.
+package format
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"todo-cli/internal/domain"
+)
+
+// Format encodes and decodes a task list.
+type Format interface {
+	Encode(w io.Writer, tasks []domain.Task) error
+	Decode(r io.Reader) ([]domain.Task, error)
+}
+
+var formats = map[string]Format{
+	"todotxt":  TodoTxt{},
+	"csv":      CSV{},
+	"markdown": Markdown{},
+	"json":     JSON{},
+}
+
+var extensions = map[string]string{
+	".txt":      "todotxt",
+	".csv":      "csv",
+	".md":       "markdown",
+	".markdown": "markdown",
+	".json":     "json",
+}
+
+// Names lists the supported format names.
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ByName returns the format registered under name.
+func ByName(name string) (Format, error) {
+	f, ok := formats[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q (want %s)", name, strings.Join(Names(), ", "))
+	}
+	return f, nil
+}
+
+// ForFile picks a format from the file extension.
+func ForFile(path string) (Format, error) {
+	name, ok := extensions[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("cannot detect format of %s, use --format", path)
+	}
+	return formats[name], nil
+}
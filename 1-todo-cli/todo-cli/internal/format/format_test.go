@@ -0,0 +1,102 @@
+package format
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"todo-cli/internal/domain"
+)
+
+func TestTodoTxt_RoundTrip(t *testing.T) {
+	input := strings.Join([]string{
+		"(A) 2026-10-01 Call mom @phone +family due:2026-10-20",
+		"x 2026-10-10 2026-10-01 Review PR +work pri:B",
+		"(C) Water plants",
+		"Plain task",
+	}, "\n") + "\n"
+
+	tasks, err := TodoTxt{}.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tasks) != 4 {
+		t.Fatalf("expected 4 tasks, got %d", len(tasks))
+	}
+
+	first := tasks[0]
+	if first.Name != "Call mom" || first.Priority != domain.PriorityHigh {
+		t.Fatalf("unexpected first task: %+v", first)
+	}
+	if !reflect.DeepEqual(first.Tags, []string{"@phone", "family"}) {
+		t.Fatalf("unexpected tags: %v", first.Tags)
+	}
+
+	second := tasks[1]
+	if !second.Done || second.CompletedAt == nil || second.Priority != domain.PriorityMedium {
+		t.Fatalf("unexpected second task: %+v", second)
+	}
+
+	var buf bytes.Buffer
+	if err := (TodoTxt{}).Encode(&buf, tasks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != input {
+		t.Fatalf("round trip mismatch:\nwant:\n%s\ngot:\n%s", input, buf.String())
+	}
+}
+
+func TestMarkdown_RoundTrip(t *testing.T) {
+	input := "# Sprint\n\n- [ ] Write tests +work\n* [X] Ship it due:2026-11-01 pri:A\nnot a task\n"
+
+	tasks, err := Markdown{}.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(tasks))
+	}
+	if tasks[0].Done || !tasks[1].Done {
+		t.Fatalf("unexpected done state: %+v", tasks)
+	}
+
+	var buf bytes.Buffer
+	if err := (Markdown{}).Encode(&buf, tasks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "- [ ] Write tests +work\n- [x] Ship it due:2026-11-01 pri:A\n"
+	if buf.String() != want {
+		t.Fatalf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestCSV_RoundTrip(t *testing.T) {
+	tasks := []domain.Task{
+		{ID: 1, Name: "Deploy, carefully", Priority: domain.PriorityHigh, Tags: []string{"ops", "@desk"}},
+		{ID: 2, Name: "Done thing", Done: true},
+	}
+
+	var buf bytes.Buffer
+	if err := (CSV{}).Encode(&buf, tasks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := CSV{}.Decode(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, tasks) {
+		t.Fatalf("expected %+v, got %+v", tasks, got)
+	}
+}
+
+func TestForFile(t *testing.T) {
+	if f, err := ForFile("todo.TXT"); err != nil || f != (TodoTxt{}) {
+		t.Fatalf("expected todotxt, got %v, %v", f, err)
+	}
+	if _, err := ForFile("tasks.xlsx"); err == nil {
+		t.Fatal("expected error for unknown extension")
+	}
+}
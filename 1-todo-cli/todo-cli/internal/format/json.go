@@ -0,0 +1,46 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"todo-cli/internal/domain"
+)
+
+// JSON writes a plain array of tasks. On import it also accepts
+// a todo data file as written by the JSON repository.
+type JSON struct{}
+
+func (JSON) Encode(w io.Writer, tasks []domain.Task) error {
+	if tasks == nil {
+		tasks = []domain.Task{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tasks)
+}
+
+func (JSON) Decode(r io.Reader) ([]domain.Task, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var tasks []domain.Task
+	if data[0] == '[' {
+		err = json.Unmarshal(data, &tasks)
+		return tasks, err
+	}
+
+	var file struct {
+		Tasks []domain.Task `json:"tasks"`
+	}
+	err = json.Unmarshal(data, &file)
+	return file.Tasks, err
+}
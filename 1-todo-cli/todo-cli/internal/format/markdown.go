@@ -0,0 +1,50 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"todo-cli/internal/domain"
+)
+
+// Markdown reads and writes GitHub-style checklists ("- [ ] task", "- [x] task").
+// Tags, due dates and priorities use the todo.txt notation inside the item text.
+// Lines that are not checklist items are ignored on import.
+type Markdown struct{}
+
+var checklistItem = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.*)$`)
+
+func (Markdown) Encode(w io.Writer, tasks []domain.Task) error {
+	for _, t := range tasks {
+		box := " "
+		if t.Done {
+			box = "x"
+		}
+		if _, err := fmt.Fprintf(w, "- [%s] %s\n", box, encodeBody(t, true)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (Markdown) Decode(r io.Reader) ([]domain.Task, error) {
+	var tasks []domain.Task
+
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		m := checklistItem.FindStringSubmatch(sc.Text())
+		if m == nil {
+			continue
+		}
+
+		t := domain.Task{Done: strings.EqualFold(m[1], "x")}
+		if err := parseBody(&t, strings.Fields(m[2])); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, sc.Err()
+}
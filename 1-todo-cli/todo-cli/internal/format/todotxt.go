@@ -0,0 +1,190 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"todo-cli/internal/domain"
+)
+
+const dateLayout = "2006-01-02"
+
+// TodoTxt implements the todo.txt format (https://github.com/todotxt/todo.txt).
+// Projects (+x) map to tags, contexts (@x) to tags starting with '@'.
+// Priorities A, B and C map to high, medium and low; D-Z import as low.
+type TodoTxt struct{}
+
+func (TodoTxt) Encode(w io.Writer, tasks []domain.Task) error {
+	for _, t := range tasks {
+		var parts []string
+
+		if t.Done {
+			parts = append(parts, "x")
+			if t.CompletedAt != nil {
+				parts = append(parts, t.CompletedAt.Format(dateLayout))
+				if t.CreatedAt != nil {
+					parts = append(parts, t.CreatedAt.Format(dateLayout))
+				}
+			}
+		} else {
+			if p := priorityLetter(t.Priority); p != "" {
+				parts = append(parts, "("+p+")")
+			}
+			if t.CreatedAt != nil {
+				parts = append(parts, t.CreatedAt.Format(dateLayout))
+			}
+		}
+
+		// The spec drops the priority of completed tasks and keeps it as pri:X.
+		parts = append(parts, encodeBody(t, t.Done))
+
+		if _, err := fmt.Fprintln(w, strings.Join(parts, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (TodoTxt) Decode(r io.Reader) ([]domain.Task, error) {
+	var tasks []domain.Task
+
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+
+		t, err := parseTodoTxtLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, sc.Err()
+}
+
+func parseTodoTxtLine(line string) (domain.Task, error) {
+	var t domain.Task
+	fields := strings.Fields(line)
+
+	if fields[0] == "x" {
+		t.Done = true
+		fields = fields[1:]
+		if d, ok := parseDate(fields); ok {
+			t.CompletedAt = &d
+			fields = fields[1:]
+		}
+	} else if p, ok := parsePriorityToken(fields[0]); ok {
+		t.Priority = p
+		fields = fields[1:]
+	}
+
+	if d, ok := parseDate(fields); ok {
+		t.CreatedAt = &d
+		fields = fields[1:]
+	}
+
+	if err := parseBody(&t, fields); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// encodeBody writes the description followed by tags and key:value
+// extensions. withPri adds the priority as a pri:X key.
+func encodeBody(t domain.Task, withPri bool) string {
+	parts := []string{t.Name}
+
+	for _, tag := range t.Tags {
+		if strings.HasPrefix(tag, "@") {
+			parts = append(parts, tag)
+		} else {
+			parts = append(parts, "+"+tag)
+		}
+	}
+	if t.Due != nil {
+		parts = append(parts, "due:"+t.Due.Format(dateLayout))
+	}
+	if p := priorityLetter(t.Priority); withPri && p != "" {
+		parts = append(parts, "pri:"+p)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// parseBody fills in name, tags, due date and pri:X from description words.
+func parseBody(t *domain.Task, fields []string) error {
+	var words []string
+
+	for _, f := range fields {
+		switch {
+		case len(f) > 1 && f[0] == '+':
+			t.Tags = append(t.Tags, f[1:])
+		case len(f) > 1 && f[0] == '@':
+			t.Tags = append(t.Tags, f)
+		case strings.HasPrefix(f, "due:"):
+			d, err := time.ParseInLocation(dateLayout, strings.TrimPrefix(f, "due:"), time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid due date %q", f)
+			}
+			t.Due = &d
+		case strings.HasPrefix(f, "pri:") && len(f) == 5:
+			if p, ok := letterPriority(f[4]); ok {
+				t.Priority = p
+				continue
+			}
+			words = append(words, f)
+		default:
+			words = append(words, f)
+		}
+	}
+
+	t.Name = strings.Join(words, " ")
+	if t.Name == "" {
+		return fmt.Errorf("task has no description")
+	}
+	return nil
+}
+
+func parseDate(fields []string) (time.Time, bool) {
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+	d, err := time.ParseInLocation(dateLayout, fields[0], time.Local)
+	return d, err == nil
+}
+
+func parsePriorityToken(s string) (domain.Priority, bool) {
+	if len(s) != 3 || s[0] != '(' || s[2] != ')' {
+		return domain.PriorityNone, false
+	}
+	return letterPriority(s[1])
+}
+
+func letterPriority(c byte) (domain.Priority, bool) {
+	switch {
+	case c == 'A':
+		return domain.PriorityHigh, true
+	case c == 'B':
+		return domain.PriorityMedium, true
+	case c >= 'C' && c <= 'Z':
+		return domain.PriorityLow, true
+	}
+	return domain.PriorityNone, false
+}
+
+func priorityLetter(p domain.Priority) string {
+	switch p {
+	case domain.PriorityHigh:
+		return "A"
+	case domain.PriorityMedium:
+		return "B"
+	case domain.PriorityLow:
+		return "C"
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+// Package fuzzy scores how well a short query matches a piece of text.
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Scoring weights: matched runes count, runs of consecutive matches and
+// matches at the start of a word or the text count more, gaps less.
+const (
+	scoreMatch       = 10
+	bonusConsecutive = 15
+	bonusWordStart   = 20
+	bonusSubstring   = 50
+	bonusPrefix      = 10
+	penaltyGap       = 1
+	maxGapPenalty    = 20
+)
+
+// Match reports whether all runes of query appear in text in order,
+// ignoring case. It returns a score, higher being better, and the rune
+// indexes of text that matched. An exact substring scores above any
+// scattered match of the same query.
+func Match(query, text string) (score int, positions []int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+
+	if i := substring(t, q); i >= 0 {
+		positions = make([]int, len(q))
+		for j := range q {
+			positions[j] = i + j
+		}
+		return rate(t, positions) + bonusSubstring, positions, true
+	}
+
+	// Jumping ahead to word starts usually gives the better match, but
+	// it can skip past runes needed later, so plain order is the fallback.
+	for _, preferWordStart := range []bool{true, false} {
+		if positions := scan(t, q, preferWordStart); positions != nil {
+			return rate(t, positions), positions, true
+		}
+	}
+	return 0, nil, false
+}
+
+// scan finds each rune of q in t in order and returns their indexes,
+// or nil if one is missing.
+func scan(t, q []rune, preferWordStart bool) []int {
+	positions := make([]int, 0, len(q))
+	start := 0
+	for _, r := range q {
+		i := index(t, r, start, false)
+		if i < 0 {
+			return nil
+		}
+		if preferWordStart && !wordStart(t, i) {
+			if j := index(t, r, i+1, true); j >= 0 {
+				i = j
+			}
+		}
+		positions = append(positions, i)
+		start = i + 1
+	}
+	return positions
+}
+
+// rate scores the matched positions within t.
+func rate(t []rune, positions []int) int {
+	score := 0
+	if positions[0] == 0 {
+		score += bonusPrefix
+	}
+	for n, i := range positions {
+		score += scoreMatch
+		if wordStart(t, i) {
+			score += bonusWordStart
+		}
+		if n > 0 {
+			if gap := i - positions[n-1] - 1; gap == 0 {
+				score += bonusConsecutive
+			} else {
+				score -= min(gap*penaltyGap, maxGapPenalty)
+			}
+		}
+	}
+	return score
+}
+
+// substring returns the index of q in t, preferring an occurrence at
+// a word start, or -1.
+func substring(t, q []rune) int {
+	first := -1
+	for i := 0; i+len(q) <= len(t); i++ {
+		if !slices.Equal(t[i:i+len(q)], q) {
+			continue
+		}
+		if wordStart(t, i) {
+			return i
+		}
+		if first < 0 {
+			first = i
+		}
+	}
+	return first
+}
+
+// index returns the first index of r in t from the given one on,
+// optionally only at word starts, or -1.
+func index(t []rune, r rune, from int, atWordStart bool) int {
+	for i := from; i < len(t); i++ {
+		if t[i] == r && (!atWordStart || wordStart(t, i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func wordStart(t []rune, i int) bool {
+	return i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1])
+}
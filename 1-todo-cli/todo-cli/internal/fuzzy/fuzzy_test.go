@@ -0,0 +1,49 @@
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query, text string
+		positions   []int
+		ok          bool
+	}{
+		{"dep", "Deploy to prod", []int{0, 1, 2}, true},
+		{"dtp", "Deploy to prod", []int{0, 7, 10}, true},
+		{"prod", "Reproduce the prod bug", []int{14, 15, 16, 17}, true},
+		{"ab", "xab a", []int{1, 2}, true},
+		{"zz", "Deploy", nil, false},
+		{"", "anything", nil, true},
+	}
+
+	for _, tt := range tests {
+		_, positions, ok := Match(tt.query, tt.text)
+		if ok != tt.ok || !reflect.DeepEqual(positions, tt.positions) {
+			t.Errorf("Match(%q, %q) = %v, %v; want %v, %v", tt.query, tt.text, positions, ok, tt.positions, tt.ok)
+		}
+	}
+}
+
+func TestMatch_Ranking(t *testing.T) {
+	ranked := []string{
+		"Deploy",              // prefix
+		"Re-deploy the app",   // substring at a word start
+		"Undeployed changes",  // substring inside a word
+		"Design plan on yard", // scattered
+	}
+
+	prev := 1 << 30
+	for _, text := range ranked {
+		score, _, ok := Match("deploy", text)
+		if !ok {
+			t.Fatalf("expected %q to match", text)
+		}
+		if score >= prev {
+			t.Fatalf("expected %q to score below the previous text, got %d >= %d", text, score, prev)
+		}
+		prev = score
+	}
+}
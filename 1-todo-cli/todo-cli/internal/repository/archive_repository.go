@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"encoding/json"
+	"todo-cli/internal/domain"
+)
+
+// ArchiveRepository keeps completed tasks moved out of a task list.
+type ArchiveRepository interface {
+	Load() ([]domain.Task, error)
+	// Append adds tasks to the archive, replacing archived tasks
+	// with the same ID.
+	Append([]domain.Task) error
+}
+
+// JSONArchive stores archived tasks as a JSON array next to the data file.
+// It relies on the task repository's lock for serialization.
+// With a Cipher set the file is encrypted like EncryptedRepository's.
+type JSONArchive struct {
+	Filename string
+	Cipher   *Cipher
+}
+
+func (a *JSONArchive) Load() ([]domain.Task, error) {
+	data, err := readFile(a.Filename, a.Cipher)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var tasks []domain.Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, corrupt(a.Filename, data, a.Cipher, err)
+	}
+
+	return tasks, nil
+}
+
+func (a *JSONArchive) Append(tasks []domain.Task) error {
+	archived, err := a.Load()
+	if err != nil {
+		return err
+	}
+
+	replaced := make(map[int]bool, len(tasks))
+	for _, t := range tasks {
+		replaced[t.ID] = true
+	}
+
+	var kept []domain.Task
+	for _, t := range archived {
+		if !replaced[t.ID] {
+			kept = append(kept, t)
+		}
+	}
+
+	data, err := json.MarshalIndent(append(kept, tasks...), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(a.Filename, data, a.Cipher)
+}
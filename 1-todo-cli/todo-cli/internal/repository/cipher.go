@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	// ErrDecrypt is returned when an encrypted file cannot be opened,
+	// either because the passphrase is wrong or the file was modified.
+	ErrDecrypt = errors.New("cannot decrypt data file (wrong passphrase?)")
+	// ErrEncrypted is returned when an encrypted file is read without a passphrase.
+	ErrEncrypted = errors.New("data file is encrypted")
+	// ErrNotEncrypted is returned when a plaintext file is read with a passphrase.
+	ErrNotEncrypted = errors.New("data file is not encrypted")
+)
+
+const (
+	sealedFormat = "todo-encrypted"
+	sealedKDF    = "pbkdf2-sha256"
+
+	// defaultIterations follows current OWASP guidance for PBKDF2-HMAC-SHA256.
+	defaultIterations = 600_000
+	maxIterations     = 10_000_000
+	saltSize          = 16
+	keySize           = 32 // AES-256
+)
+
+// sealed is the on-disk layout of an encrypted file. The header fields
+// are authenticated along with the ciphertext.
+type sealed struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+func (s sealed) additionalData() []byte {
+	return fmt.Appendf(nil, "%s/%d/%s/%d/%x", s.Format, s.Version, s.KDF, s.Iterations, s.Salt)
+}
+
+// Cipher seals files with AES-256-GCM under a key derived from a
+// passphrase with PBKDF2. Deriving the key is deliberately slow, so
+// the key for the last salt seen is kept and files written afterwards
+// reuse that salt with a fresh nonce.
+type Cipher struct {
+	passphrase string
+	iterations int
+
+	salt []byte
+	key  []byte
+}
+
+func NewCipher(passphrase string) *Cipher {
+	return &Cipher{passphrase: passphrase, iterations: defaultIterations}
+}
+
+// Seal encrypts plaintext into a self-describing envelope.
+func (c *Cipher) Seal(plaintext []byte) ([]byte, error) {
+	if c.key == nil {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		if err := c.derive(salt, c.iterations); err != nil {
+			return nil, err
+		}
+	}
+
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+
+	s := sealed{
+		Format:     sealedFormat,
+		Version:    1,
+		KDF:        sealedKDF,
+		Iterations: c.iterations,
+		Salt:       c.salt,
+		Nonce:      make([]byte, aead.NonceSize()),
+	}
+	if _, err := rand.Read(s.Nonce); err != nil {
+		return nil, err
+	}
+	s.Data = aead.Seal(nil, s.Nonce, plaintext, s.additionalData())
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// Open decrypts an envelope written by Seal.
+func (c *Cipher) Open(data []byte) ([]byte, error) {
+	var s sealed
+	if err := json.Unmarshal(data, &s); err != nil || s.Format != sealedFormat {
+		return nil, ErrNotEncrypted
+	}
+	if s.Version != 1 || s.KDF != sealedKDF || s.Iterations < 1 || s.Iterations > maxIterations {
+		return nil, fmt.Errorf("%w: unsupported format", ErrDecrypt)
+	}
+
+	if !bytes.Equal(s.Salt, c.salt) || s.Iterations != c.iterations {
+		if err := c.derive(s.Salt, s.Iterations); err != nil {
+			return nil, err
+		}
+	}
+
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	plaintext, err := aead.Open(nil, s.Nonce, s.Data, s.additionalData())
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func (c *Cipher) derive(salt []byte, iterations int) error {
+	key, err := pbkdf2.Key(sha256.New, c.passphrase, salt, iterations, keySize)
+	if err != nil {
+		return err
+	}
+	c.salt, c.key, c.iterations = salt, key, iterations
+	return nil
+}
+
+func (c *Cipher) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// IsEncrypted reports whether data was written by Cipher.Seal.
+func IsEncrypted(data []byte) bool {
+	if !bytes.Contains(data, []byte(sealedFormat)) {
+		return false
+	}
+
+	var s struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal(data, &s) == nil && s.Format == sealedFormat
+}
+
+// IsEncryptedFile reports whether the file at path is encrypted.
+// A missing file is not.
+func IsEncryptedFile(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return IsEncrypted(data), nil
+}
+
+// readFile reads path, decrypting it with c unless c is nil.
+// A missing file reads as empty. Reading an encrypted file without
+// a cipher fails rather than returning the envelope as content.
+func readFile(path string, c *Cipher) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	switch {
+	case c != nil:
+		data, err = c.Open(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, path)
+		}
+	case IsEncrypted(data):
+		return nil, fmt.Errorf("%w: %s", ErrEncrypted, path)
+	}
+	return data, nil
+}
+
+// writeFile encrypts data with c unless c is nil and replaces path
+// atomically. Encrypted files are only readable by the owner.
+func writeFile(path string, data []byte, c *Cipher) error {
+	if c == nil {
+		return writeFileAtomic(path, data, 0644)
+	}
+
+	data, err := c.Seal(data)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0600)
+}
+
+// SealFile encrypts the file at path in place. Missing and already
+// encrypted files are skipped, so an interrupted conversion can be rerun.
+func SealFile(path string, c *Cipher) error {
+	if encrypted, err := IsEncryptedFile(path); err != nil || encrypted {
+		return err
+	}
+
+	data, err := readFile(path, nil)
+	if err != nil || data == nil {
+		return err
+	}
+	return writeFile(path, data, c)
+}
+
+// UnsealFile decrypts the file at path in place. Missing and plaintext
+// files are skipped, so an interrupted conversion can be rerun.
+func UnsealFile(path string, c *Cipher) error {
+	if encrypted, err := IsEncryptedFile(path); err != nil || !encrypted {
+		return err
+	}
+
+	data, err := readFile(path, c)
+	if err != nil || data == nil {
+		return err
+	}
+	return writeFile(path, data, nil)
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// ErrCorrupt is returned when a data file exists but cannot be decoded.
+var ErrCorrupt = errors.New("data file is corrupt")
+
+// ErrLocked is returned when another process holds the lock for too long.
+var ErrLocked = errors.New("data file is locked by another process")
+
+const (
+	lockTimeout    = 10 * time.Second
+	lockRetryDelay = 50 * time.Millisecond
+)
+
+// lockFile takes an exclusive advisory lock on path+".lock",
+// waiting up to lockTimeout for other processes to release it.
+func lockFile(path string) (func() error, error) {
+	fl := flock.New(path + ".lock")
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	ok, err := fl.TryLockContext(ctx, lockRetryDelay)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLocked, path)
+	}
+
+	return fl.Unlock, nil
+}
+
+// writeFileAtomic replaces path with data so that readers and crashes
+// only ever observe the old or the new content, never a partial write.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+
+	// Persist the rename itself.
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// backupCorrupt copies an undecodable file aside and returns an ErrCorrupt
+// naming the backup. The backup name is derived from the content so
+// repeated reads of the same broken file do not pile up copies.
+func backupCorrupt(path string, data []byte, cause error) error {
+	sum := sha256.Sum256(data)
+	backup := path + ".corrupt-" + hex.EncodeToString(sum[:4])
+
+	if _, err := os.Stat(backup); os.IsNotExist(err) {
+		if err := os.WriteFile(backup, data, 0600); err != nil {
+			return fmt.Errorf("%w: %s: %v (backup failed: %v)", ErrCorrupt, path, cause, err)
+		}
+	}
+
+	return fmt.Errorf("%w: %s: %v (backup saved to %s)", ErrCorrupt, path, cause, backup)
+}
+
+// corrupt reports an undecodable file. Plaintext files are backed up
+// first; decrypted content is never written out in the clear.
+func corrupt(path string, data []byte, c *Cipher, cause error) error {
+	if c != nil {
+		return fmt.Errorf("%w: %s: %v", ErrCorrupt, path, cause)
+	}
+	return backupCorrupt(path, data, cause)
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"encoding/json"
+	"todo-cli/internal/domain"
+)
+
+// JournalRepository persists the undo/redo history of a task list.
+type JournalRepository interface {
+	Load() (domain.Journal, error)
+	Save(domain.Journal) error
+}
+
+// JSONJournal stores the journal as a JSON file next to the data file.
+// It relies on the task repository's lock for serialization.
+// With a Cipher set the file is encrypted like EncryptedRepository's.
+type JSONJournal struct {
+	Filename string
+	Cipher   *Cipher
+}
+
+func (j *JSONJournal) Load() (domain.Journal, error) {
+	var journal domain.Journal
+
+	data, err := readFile(j.Filename, j.Cipher)
+	if err != nil || data == nil {
+		return journal, err
+	}
+
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return domain.Journal{}, corrupt(j.Filename, data, j.Cipher, err)
+	}
+
+	return journal, nil
+}
+
+func (j *JSONJournal) Save(journal domain.Journal) error {
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(j.Filename, data, j.Cipher)
+}
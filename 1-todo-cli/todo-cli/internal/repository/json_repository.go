@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"bytes"
 	"encoding/json"
-	"os"
+	"errors"
+	"fmt"
 	"todo-cli/internal/domain"
 )
 
@@ -10,27 +12,139 @@ type JSONRepository struct {
 	Filename string
 }
 
+// EncryptedRepository stores tasks like JSONRepository, but sealed with
+// a passphrase-derived key so the file cannot be read or altered without it.
+type EncryptedRepository struct {
+	Filename string
+	Cipher   *Cipher
+}
+
+// taskFile is the on-disk layout, see CurrentVersion. NextID is the
+// high-water mark of issued IDs so deleting the newest task never frees its ID.
+type taskFile struct {
+	Version int           `json:"version"`
+	NextID  int           `json:"next_id"`
+	Tasks   []domain.Task `json:"tasks"`
+}
+
 func (r *JSONRepository) Load() ([]domain.Task, error) {
-	var tasks []domain.Task
+	return loadTasks(r.Filename, nil)
+}
+
+func (r *JSONRepository) Save(tasks []domain.Task) error {
+	return saveTasks(r.Filename, tasks, nil)
+}
+
+// Lock serializes Load/modify/Save cycles across processes.
+func (r *JSONRepository) Lock() (func() error, error) {
+	return lockFile(r.Filename)
+}
 
-	data, err := os.ReadFile(r.Filename)
+func (r *JSONRepository) NextID() (int, error) {
+	return loadNextID(r.Filename, nil)
+}
+
+func (r *EncryptedRepository) Load() ([]domain.Task, error) {
+	return loadTasks(r.Filename, r.Cipher)
+}
+
+func (r *EncryptedRepository) Save(tasks []domain.Task) error {
+	return saveTasks(r.Filename, tasks, r.Cipher)
+}
+
+func (r *EncryptedRepository) Lock() (func() error, error) {
+	return lockFile(r.Filename)
+}
+
+func (r *EncryptedRepository) NextID() (int, error) {
+	return loadNextID(r.Filename, r.Cipher)
+}
+
+func loadTasks(path string, c *Cipher) ([]domain.Task, error) {
+	f, err := readTaskFile(path, c)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return tasks, nil // return empty slice if file not exist
-		}
 		return nil, err
 	}
+	return f.Tasks, nil
+}
 
-	err = json.Unmarshal(data, &tasks)
+func saveTasks(path string, tasks []domain.Task, c *Cipher) error {
+	f, err := readTaskFile(path, c)
+	if err != nil {
+		return err
+	}
 
-	return tasks, err
-}
+	f.Version = CurrentVersion
+	f.Tasks = tasks
+	f.NextID = nextID(f.NextID, tasks)
 
-func (r *JSONRepository) Save(tasks []domain.Task) error {
-	data, err := json.MarshalIndent(tasks, "", "  ")
+	data, err := json.MarshalIndent(f, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(r.Filename, data, 0644)
+	return writeFile(path, data, c)
+}
+
+func loadNextID(path string, c *Cipher) (int, error) {
+	f, err := readTaskFile(path, c)
+	if err != nil {
+		return 0, err
+	}
+	return nextID(f.NextID, f.Tasks), nil
+}
+
+// readTaskFile decodes the data file, migrating older layouts to the
+// current one. A file that fails to decode is backed up and reported as
+// ErrCorrupt, and one from a newer version as ErrUnsupportedVersion;
+// either also stops Save from overwriting it.
+func readTaskFile(path string, c *Cipher) (taskFile, error) {
+	f, _, err := decodeTaskFile(path, c)
+	return f, err
+}
+
+func decodeTaskFile(path string, c *Cipher) (taskFile, int, error) {
+	var f taskFile
+
+	data, err := readFile(path, c)
+	if err != nil {
+		return f, 0, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return f, CurrentVersion, nil
+	}
+
+	migrated, version, err := migrate(data)
+	if errors.Is(err, ErrUnsupportedVersion) {
+		return f, version, fmt.Errorf("%w: %s", err, path)
+	}
+	if err == nil {
+		err = json.Unmarshal(migrated, &f)
+	}
+	if err != nil {
+		return taskFile{}, version, corrupt(path, data, c, err)
+	}
+
+	return f, version, nil
+}
+
+// FileVersion returns the layout version the data file at path is
+// stored in. Missing and empty files count as current.
+func FileVersion(path string, c *Cipher) (int, error) {
+	_, version, err := decodeTaskFile(path, c)
+	return version, err
+}
+
+// nextID returns the next free ID given the stored counter
+// and the tasks currently in the list.
+func nextID(stored int, tasks []domain.Task) int {
+	next := max(stored, 1)
+	for _, t := range tasks {
+		if t.ID >= next {
+			next = t.ID + 1
+		}
+	}
+	return next
 }
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+	"todo-cli/internal/domain"
+)
+
+func TestJSONRepository_MigratesBareArray(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	legacy := `[{"id": 1, "name": "A", "done": false}, {"id": 2, "name": "B", "done": true}]`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &JSONRepository{Filename: path}
+
+	tasks, err := repo.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []domain.Task{
+		{ID: 1, Name: "A"},
+		{ID: 2, Name: "B", Done: true},
+	}
+	if !reflect.DeepEqual(tasks, expected) {
+		t.Fatalf("expected %v, got %v", expected, tasks)
+	}
+
+	id, err := repo.NextID()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != 3 {
+		t.Fatalf("expected next ID 3, got %d", id)
+	}
+}
+
+func TestJSONRepository_NextIDSurvivesDelete(t *testing.T) {
+	repo := &JSONRepository{Filename: filepath.Join(t.TempDir(), "tasks.json")}
+
+	if err := repo.Save([]domain.Task{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save([]domain.Task{{ID: 1, Name: "A"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := repo.NextID()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != 3 {
+		t.Fatalf("expected deleted ID 2 not to be reused, got %d", id)
+	}
+}
+
+func TestJSONRepository_CorruptFileIsKept(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	broken := []byte(`{"next_id": 3, "tasks": [{"id": 1,`)
+	if err := os.WriteFile(path, broken, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &JSONRepository{Filename: path}
+
+	if _, err := repo.Load(); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected ErrCorrupt, got %v", err)
+	}
+
+	if err := repo.Save([]domain.Task{{ID: 1, Name: "A"}}); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected Save to refuse with ErrCorrupt, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(broken) {
+		t.Fatal("corrupt file was overwritten")
+	}
+
+	backups, _ := filepath.Glob(path + ".corrupt-*")
+	if len(backups) != 1 {
+		t.Fatalf("expected one backup, got %v", backups)
+	}
+}
+
+func TestJSONRepository_MigratesToCurrentVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	v1 := `{"next_id": 5, "tasks": [{"id": 1, "name": "A", "done": false}]}`
+	if err := os.WriteFile(path, []byte(v1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &JSONRepository{Filename: path}
+
+	if v, err := FileVersion(path, nil); err != nil || v != 1 {
+		t.Fatalf("expected version 1, got %d (%v)", v, err)
+	}
+
+	tasks, err := repo.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.Save(tasks); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, err := FileVersion(path, nil); err != nil || v != CurrentVersion {
+		t.Fatalf("expected version %d after save, got %d (%v)", CurrentVersion, v, err)
+	}
+	if id, err := repo.NextID(); err != nil || id != 5 {
+		t.Fatalf("expected next ID 5 to survive the migration, got %d (%v)", id, err)
+	}
+}
+
+func TestJSONRepository_NewerVersionIsKept(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	newer := []byte(`{"version": 99, "next_id": 2, "tasks": [{"id": 1, "name": "A"}]}`)
+	if err := os.WriteFile(path, newer, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &JSONRepository{Filename: path}
+
+	if _, err := repo.Load(); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("expected ErrUnsupportedVersion, got %v", err)
+	}
+	if err := repo.Save(nil); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("expected Save to refuse with ErrUnsupportedVersion, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(newer) {
+		t.Fatal("newer file was overwritten")
+	}
+}
+
+func TestJSONRepository_LockIsExclusive(t *testing.T) {
+	repo := &JSONRepository{Filename: filepath.Join(t.TempDir(), "tasks.json")}
+
+	unlock, err := repo.Lock()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		other := &JSONRepository{Filename: repo.Filename}
+		unlockOther, err := other.Lock()
+		if err == nil {
+			unlockOther()
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second lock acquired while first was held")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-acquired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("second lock not acquired after release")
+	}
+}
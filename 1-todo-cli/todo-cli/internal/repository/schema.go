@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedVersion is returned for a data file written by a newer
+// version of todo. Such a file is neither read nor overwritten.
+var ErrUnsupportedVersion = errors.New("data file was written by a newer version of todo")
+
+// CurrentVersion is the data file layout written by Save:
+//
+//	0: a bare JSON array of tasks
+//	1: {"next_id": ..., "tasks": [...]}
+//	2: {"version": 2, "next_id": ..., "tasks": [...]}
+//
+// Older layouts are migrated when read and saved in the current one.
+const CurrentVersion = 2
+
+// migrations[v] upgrades the raw content of a version v file to v+1.
+// They work on plain JSON rather than domain.Task, so each step keeps
+// seeing the layout it was written for as the task type evolves.
+var migrations = [CurrentVersion]func([]byte) ([]byte, error){
+	migrateBareArray,
+	migrateAddVersion,
+}
+
+// migrateBareArray wraps the task array in an object, keeping task IDs
+// and recording the ID counter they imply.
+func migrateBareArray(data []byte) ([]byte, error) {
+	var tasks []json.RawMessage
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, err
+	}
+
+	next := 1
+	for _, raw := range tasks {
+		var t struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return nil, err
+		}
+		next = max(next, t.ID+1)
+	}
+
+	return json.Marshal(map[string]any{"next_id": next, "tasks": tasks})
+}
+
+// migrateAddVersion adds the version field.
+func migrateAddVersion(data []byte) ([]byte, error) {
+	var f map[string]json.RawMessage
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	f["version"] = json.RawMessage("2")
+	return json.Marshal(f)
+}
+
+// fileVersion detects the layout of non-empty data file content.
+func fileVersion(data []byte) (int, error) {
+	if data[0] == '[' {
+		return 0, nil
+	}
+
+	var head struct {
+		Version *int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return 0, err
+	}
+	if head.Version == nil {
+		return 1, nil
+	}
+	if *head.Version < 2 {
+		return 0, fmt.Errorf("invalid version %d", *head.Version)
+	}
+	return *head.Version, nil
+}
+
+// migrate upgrades data file content to CurrentVersion one step at a
+// time and returns it along with the version it was found in.
+func migrate(data []byte) ([]byte, int, error) {
+	from, err := fileVersion(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from > CurrentVersion {
+		return nil, from, fmt.Errorf("%w (version %d, this one reads up to %d)", ErrUnsupportedVersion, from, CurrentVersion)
+	}
+
+	for v := from; v < CurrentVersion; v++ {
+		if data, err = migrations[v](data); err != nil {
+			return nil, from, fmt.Errorf("migrating from version %d: %w", v, err)
+		}
+	}
+	return data, from, nil
+}
@@ -2,7 +2,14 @@ package repository
 
 import "todo-cli/internal/domain"
 
+// TaskRepository persists tasks.
+// NextID hands out identifiers that are never reused,
+// even after the task holding them has been deleted.
+// Lock takes an exclusive lock shared with other processes
+// and returns the function that releases it.
 type TaskRepository interface {
 	Load() ([]domain.Task, error)
 	Save([]domain.Task) error
+	NextID() (int, error)
+	Lock() (unlock func() error, err error)
 }
@@ -0,0 +1,343 @@
+// Package server exposes a task list as a JSON HTTP API. Every change
+// goes through the usecase, so it takes the same file lock as the CLI
+// and both can be used on the list at the same time.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+	"todo-cli/internal/usecase"
+)
+
+// maxBodySize bounds request bodies; a task is a few hundred bytes.
+const maxBodySize = 1 << 20
+
+// Server handles the API requests for one task list.
+type Server struct {
+	tasks *usecase.TaskUsecase
+	now   func() time.Time
+
+	// mu serializes requests. The file lock keeps other processes out,
+	// but the repository's cipher caches its key and is not safe for
+	// concurrent use.
+	mu  sync.Mutex
+	mux *http.ServeMux
+}
+
+// New returns a handler serving the API:
+//
+//	GET    /tasks             list tasks (status, tag, q, overdue, sort, all)
+//	POST   /tasks             add a task
+//	GET    /tasks/{id}        get a task
+//	PATCH  /tasks/{id}        rename a task
+//	DELETE /tasks/{id}        delete a task (children=refuse|delete|promote)
+//	POST   /tasks/{id}/done   mark a task done (cascade=true for subtasks)
+//	DELETE /tasks/{id}/done   mark a task not done
+func New(u *usecase.TaskUsecase) *Server {
+	s := &Server{tasks: u, now: time.Now, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /tasks", s.list)
+	s.mux.HandleFunc("POST /tasks", s.add)
+	s.mux.HandleFunc("GET /tasks/{id}", s.get)
+	s.mux.HandleFunc("PATCH /tasks/{id}", s.edit)
+	s.mux.HandleFunc("DELETE /tasks/{id}", s.delete)
+	s.mux.HandleFunc("POST /tasks/{id}/done", s.done)
+	s.mux.HandleFunc("DELETE /tasks/{id}/done", s.undone)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := usecase.ListFilter{
+		Tag:  q.Get("tag"),
+		Text: q.Get("q"),
+	}
+
+	switch q.Get("status") {
+	case "", "all":
+		filter.Status = usecase.StatusAll
+	case "open":
+		filter.Status = usecase.StatusOpen
+	case "done":
+		filter.Status = usecase.StatusDone
+	default:
+		fail(w, fmt.Errorf("%w: status must be all, open or done", domain.ErrInvalidInput))
+		return
+	}
+
+	switch q.Get("sort") {
+	case "":
+		filter.Sort = usecase.SortNone
+	case "due":
+		filter.Sort = usecase.SortDue
+	case "priority":
+		filter.Sort = usecase.SortPriority
+	default:
+		fail(w, fmt.Errorf("%w: sort must be due or priority", domain.ErrInvalidInput))
+		return
+	}
+
+	var err error
+	if filter.Overdue, err = boolParam(q.Get("overdue"), "overdue"); err != nil {
+		fail(w, err)
+		return
+	}
+	if filter.Archived, err = boolParam(q.Get("all"), "all"); err != nil {
+		fail(w, err)
+		return
+	}
+
+	tasks, err := s.tasks.Query(filter)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	if tasks == nil {
+		tasks = []domain.Task{}
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+// addRequest is the body of POST /tasks. Due takes the same forms
+// as the --due flag, e.g. "2026-11-01" or "next fri".
+type addRequest struct {
+	Name     string   `json:"name"`
+	Due      string   `json:"due"`
+	Priority string   `json:"priority"`
+	Tags     []string `json:"tags"`
+	ParentID int      `json:"parent_id"`
+	Repeat   string   `json:"repeat"`
+}
+
+func (s *Server) add(w http.ResponseWriter, r *http.Request) {
+	var req addRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	var opts []usecase.AddOption
+
+	if req.Due != "" {
+		due, err := dateparse.Parse(req.Due, s.now())
+		if err != nil {
+			fail(w, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err))
+			return
+		}
+		opts = append(opts, usecase.WithDue(due))
+	}
+
+	if req.Priority != "" {
+		p, err := domain.ParsePriority(req.Priority)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		opts = append(opts, usecase.WithPriority(p))
+	}
+
+	if len(req.Tags) > 0 {
+		opts = append(opts, usecase.WithTags(req.Tags...))
+	}
+
+	if req.Repeat != "" {
+		rule, err := domain.ParseRecurrence(req.Repeat)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		opts = append(opts, usecase.WithRecurrence(rule))
+	}
+
+	if req.ParentID != 0 {
+		opts = append(opts, usecase.WithParent(req.ParentID))
+	}
+
+	task, err := s.tasks.Add(req.Name, opts...)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/tasks/%d", task.ID))
+	writeJSON(w, http.StatusCreated, task)
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	task, err := s.tasks.Get(id)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+// editRequest is the body of PATCH /tasks/{id}.
+type editRequest struct {
+	Name *string `json:"name"`
+}
+
+func (s *Server) edit(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req editRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == nil {
+		fail(w, fmt.Errorf("%w: nothing to change", domain.ErrInvalidInput))
+		return
+	}
+
+	task, err := s.tasks.Rename(id, *req.Name)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var policy usecase.ChildPolicy
+	switch r.URL.Query().Get("children") {
+	case "", "refuse":
+		policy = usecase.ChildrenRefuse
+	case "delete":
+		policy = usecase.ChildrenDelete
+	case "promote":
+		policy = usecase.ChildrenPromote
+	default:
+		fail(w, fmt.Errorf("%w: children must be refuse, delete or promote", domain.ErrInvalidInput))
+		return
+	}
+
+	removed, err := s.tasks.Delete([]int{id}, policy)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, removed)
+}
+
+func (s *Server) done(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	cascade, err := boolParam(r.URL.Query().Get("cascade"), "cascade")
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	result, err := s.tasks.MarkDone([]int{id}, cascade)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) undone(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	reopened, err := s.tasks.Reopen([]int{id})
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reopened)
+}
+
+func pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	raw := r.PathValue("id")
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		fail(w, fmt.Errorf("%w: task ID %q", domain.ErrInvalidInput, raw))
+		return 0, false
+	}
+	return id, true
+}
+
+func boolParam(raw, name string) (bool, error) {
+	if raw == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s must be true or false", domain.ErrInvalidInput, name)
+	}
+	return b, nil
+}
+
+// decode reads a JSON body into v, rejecting unknown fields so that
+// typos are reported instead of silently ignored.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		fail(w, fmt.Errorf("%w: request body: %v", domain.ErrInvalidInput, err))
+		return false
+	}
+	return true
+}
+
+// status maps an error to the HTTP status for its class, mirroring
+// the CLI's exit codes.
+func status(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrLocked):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func fail(w http.ResponseWriter, err error) {
+	writeJSON(w, status(err), map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+	"todo-cli/internal/usecase"
+)
+
+func do(t *testing.T, h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Session(t *testing.T) {
+	repo := &repository.JSONRepository{Filename: filepath.Join(t.TempDir(), "tasks.json")}
+	h := New(usecase.NewTaskUsecase(repo))
+
+	rec := do(t, h, "POST", "/tasks", `{"name": "Write", "priority": "high", "tags": ["work"]}`)
+	if rec.Code != http.StatusCreated || rec.Header().Get("Location") != "/tasks/1" {
+		t.Fatalf("add: got %d %q: %s", rec.Code, rec.Header().Get("Location"), rec.Body)
+	}
+	do(t, h, "POST", "/tasks", `{"name": "Test", "parent_id": 1}`)
+
+	rec = do(t, h, "PATCH", "/tasks/2", `{"name": "Unit test"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("edit: got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = do(t, h, "POST", "/tasks/1/done?cascade=true", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("done: got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = do(t, h, "GET", "/tasks?status=done", "")
+	var tasks []domain.Task
+	if err := json.Unmarshal(rec.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("list: %v: %s", err, rec.Body)
+	}
+	if len(tasks) != 2 || tasks[1].Name != "Unit test" || tasks[0].Priority != domain.PriorityHigh {
+		t.Fatalf("list: unexpected tasks %+v", tasks)
+	}
+
+	// The parent still has a subtask, so a plain delete is refused.
+	if rec := do(t, h, "DELETE", "/tasks/1", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("delete: expected 409, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do(t, h, "DELETE", "/tasks/1?children=delete", ""); rec.Code != http.StatusOK {
+		t.Fatalf("delete: got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do(t, h, "GET", "/tasks/2", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("get: expected 404, got %d", rec.Code)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	repo := &repository.JSONRepository{Filename: filepath.Join(t.TempDir(), "tasks.json")}
+	h := New(usecase.NewTaskUsecase(repo))
+
+	tests := []struct {
+		method, target, body string
+		want                 int
+	}{
+		{"POST", "/tasks", `{"name": ""}`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"name": "A", "prio": "high"}`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"name": "A", "priority": "urgent"}`, http.StatusBadRequest},
+		{"POST", "/tasks", `not json`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"name": "A", "parent_id": 9}`, http.StatusNotFound},
+		{"GET", "/tasks?status=later", "", http.StatusBadRequest},
+		{"GET", "/tasks/abc", "", http.StatusBadRequest},
+		{"PATCH", "/tasks/1", `{}`, http.StatusBadRequest},
+		{"POST", "/tasks/9/done", "", http.StatusNotFound},
+		{"PUT", "/tasks/1", "", http.StatusMethodNotAllowed},
+		{"GET", "/nope", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		rec := do(t, h, tt.method, tt.target, tt.body)
+		if rec.Code != tt.want {
+			t.Errorf("%s %s %s: expected %d, got %d: %s", tt.method, tt.target, tt.body, tt.want, rec.Code, rec.Body)
+		}
+	}
+}
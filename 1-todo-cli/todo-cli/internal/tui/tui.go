@@ -0,0 +1,421 @@
+// Package tui is an interactive terminal front end for a task list.
+// Every change goes through the usecase, so it is locked, saved and
+// journaled exactly like the equivalent command.
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/usecase"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Run shows the list until the user quits.
+func Run(u *usecase.TaskUsecase, list string) error {
+	_, err := tea.NewProgram(New(u, list), tea.WithAltScreen()).Run()
+	return err
+}
+
+type mode int
+
+const (
+	modeBrowse mode = iota
+	modeAdd
+	modeAddSubtask
+	modeEdit
+	modeFilter
+	modeConfirmDelete
+)
+
+// row is one visible task, flattened from the subtask tree.
+type row struct {
+	task        domain.Task
+	depth       int
+	done, total int
+	blocked     bool
+}
+
+// Model is the bubbletea model of the task browser.
+type Model struct {
+	tasks  *usecase.TaskUsecase
+	list   string
+	filter usecase.ListFilter
+
+	rows   []row
+	cursor int
+	offset int
+	height int
+
+	mode   mode
+	input  textinput.Model
+	status string
+	err    error
+	now    func() time.Time
+}
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	doneStyle     = lipgloss.NewStyle().Faint(true).Strikethrough(true)
+	overdueStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	helpStyle     = lipgloss.NewStyle().Faint(true)
+)
+
+const help = "j/k move · space done · a add · A subtask · e edit · d delete · / filter · f status · J/K reorder · u undo · ^r redo · q quit"
+
+// New loads the list into a model ready to run.
+func New(u *usecase.TaskUsecase, list string) Model {
+	input := textinput.New()
+	input.CharLimit = 200
+
+	m := Model{tasks: u, list: list, input: input, now: time.Now}
+	m.reload(0)
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.scroll()
+		return m, nil
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case modeBrowse:
+			return m.browse(msg)
+		case modeConfirmDelete:
+			return m.confirmDelete(msg)
+		default:
+			return m.edit(msg)
+		}
+	}
+	return m, nil
+}
+
+// browse handles keys while moving through the list.
+func (m Model) browse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status, m.err = "", nil
+	selected, ok := m.selected()
+
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		m.cursor--
+	case "down", "j":
+		m.cursor++
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(m.rows) - 1
+	case "a":
+		m.prompt(modeAdd, "Add: ", "")
+	case "f":
+		m.filter.Status = (m.filter.Status + 1) % 3
+		m.reload(selected.ID)
+	case "/":
+		m.prompt(modeFilter, "Filter: ", m.filter.Text)
+	case "u":
+		m.journal(m.tasks.Undo, "Undone")
+	case "ctrl+r":
+		m.journal(m.tasks.Redo, "Redone")
+	}
+
+	if !ok {
+		m.scroll()
+		return m, nil
+	}
+
+	switch msg.String() {
+	case " ", "x":
+		m.toggle(selected)
+	case "A":
+		m.prompt(modeAddSubtask, fmt.Sprintf("Add under #%d: ", selected.ID), "")
+	case "e":
+		m.prompt(modeEdit, fmt.Sprintf("Rename #%d: ", selected.ID), selected.Name)
+	case "d":
+		m.mode = modeConfirmDelete
+	case "K", "shift+up":
+		m.err = m.tasks.Move(selected.ID, -1)
+		m.reload(selected.ID)
+	case "J", "shift+down":
+		m.err = m.tasks.Move(selected.ID, 1)
+		m.reload(selected.ID)
+	}
+
+	m.scroll()
+	return m, nil
+}
+
+// edit handles keys while the text input is active.
+func (m Model) edit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.mode == modeFilter {
+			m.filter.Text = ""
+			m.reload(m.selectedID())
+		}
+		m.mode = modeBrowse
+		return m, nil
+	case tea.KeyEnter:
+		m.submit(strings.TrimSpace(m.input.Value()))
+		m.mode = modeBrowse
+		m.scroll()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	// The filter narrows the list as you type.
+	if m.mode == modeFilter {
+		m.filter.Text = m.input.Value()
+		m.reload(m.selectedID())
+	}
+	return m, cmd
+}
+
+func (m *Model) submit(value string) {
+	selected, _ := m.selected()
+
+	switch m.mode {
+	case modeAdd, modeAddSubtask:
+		if value == "" {
+			return
+		}
+		var opts []usecase.AddOption
+		if m.mode == modeAddSubtask {
+			opts = append(opts, usecase.WithParent(selected.ID))
+		}
+		task, err := m.tasks.Add(value, opts...)
+		m.err = err
+		if err == nil {
+			m.status = fmt.Sprintf("Added #%d", task.ID)
+		}
+		m.reload(task.ID)
+	case modeEdit:
+		_, m.err = m.tasks.Rename(selected.ID, value)
+		m.reload(selected.ID)
+	case modeFilter:
+		m.filter.Text = value
+		m.reload(selected.ID)
+	}
+}
+
+func (m Model) confirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mode = modeBrowse
+
+	selected, ok := m.selected()
+	if !ok || msg.String() != "y" {
+		return m, nil
+	}
+
+	removed, err := m.tasks.Delete([]int{selected.ID}, usecase.ChildrenDelete)
+	m.err = err
+	if err == nil {
+		m.status = fmt.Sprintf("Deleted %d task(s)", len(removed))
+	}
+	m.reload(0)
+	m.scroll()
+	return m, nil
+}
+
+func (m *Model) toggle(t domain.Task) {
+	if t.Done {
+		_, m.err = m.tasks.Reopen([]int{t.ID})
+		m.reload(t.ID)
+		return
+	}
+
+	result, err := m.tasks.MarkDone([]int{t.ID}, false)
+	m.err = err
+	for _, next := range result.Next {
+		m.status = fmt.Sprintf("Next occurrence #%d due %s", next.ID, dateparse.Format(*next.Due))
+	}
+	m.reload(t.ID)
+}
+
+func (m *Model) journal(step func() (domain.Operation, error), verb string) {
+	op, err := step()
+	m.err = err
+	if err == nil {
+		m.status = verb + " " + op.Name
+	}
+	m.reload(m.selectedID())
+}
+
+func (m *Model) prompt(md mode, prompt, value string) {
+	m.mode = md
+	m.input.Prompt = prompt
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.input.Focus()
+}
+
+// reload reads the list again and keeps the cursor on keepID if it
+// is still visible.
+func (m *Model) reload(keepID int) {
+	roots, err := m.tasks.Tree(m.filter)
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	m.rows = nil
+	var walk func(nodes []*domain.TaskNode, depth int)
+	walk = func(nodes []*domain.TaskNode, depth int) {
+		for _, n := range nodes {
+			if n.Task.ID == keepID {
+				m.cursor = len(m.rows)
+			}
+			m.rows = append(m.rows, row{task: n.Task, depth: depth, done: n.DoneChildren, total: n.TotalChildren, blocked: len(n.Blockers) > 0})
+			walk(n.Children, depth+1)
+		}
+	}
+	walk(roots, 0)
+	m.scroll()
+}
+
+// scroll clamps the cursor and keeps it inside the visible window.
+func (m *Model) scroll() {
+	m.cursor = max(0, min(m.cursor, len(m.rows)-1))
+
+	visible := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.rows)-visible))
+}
+
+// visibleRows is the window height minus the title, status and help lines.
+func (m Model) visibleRows() int {
+	if m.height == 0 {
+		return len(m.rows)
+	}
+	return max(1, m.height-4)
+}
+
+func (m Model) selected() (domain.Task, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return domain.Task{}, false
+	}
+	return m.rows[m.cursor].task, true
+}
+
+func (m Model) selectedID() int {
+	t, _ := m.selected()
+	return t.ID
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+
+	title := "todo · " + m.list
+	switch m.filter.Status {
+	case usecase.StatusOpen:
+		title += " · open"
+	case usecase.StatusDone:
+		title += " · done"
+	}
+	if m.filter.Text != "" {
+		title += fmt.Sprintf(" · %q", m.filter.Text)
+	}
+	b.WriteString(titleStyle.Render(title) + "\n\n")
+
+	if len(m.rows) == 0 {
+		b.WriteString(helpStyle.Render("  No tasks. Press a to add one.") + "\n")
+	}
+
+	now := m.now()
+	end := min(len(m.rows), m.offset+m.visibleRows())
+	for i := m.offset; i < end; i++ {
+		line := m.renderRow(m.rows[i], now)
+		if i == m.cursor {
+			line = selectedStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.mode == modeConfirmDelete:
+		selected, _ := m.selected()
+		question := fmt.Sprintf("Delete #%d %s", selected.ID, selected.Name)
+		if m.rows[m.cursor].total > 0 {
+			question += " and its subtasks"
+		}
+		b.WriteString(question + "? (y/n)")
+	case m.mode != modeBrowse:
+		b.WriteString(m.input.View())
+	case m.err != nil:
+		b.WriteString(errorStyle.Render("Error: " + m.err.Error()))
+	case m.status != "":
+		b.WriteString(m.status)
+	default:
+		b.WriteString(helpStyle.Render(help))
+	}
+
+	return b.String()
+}
+
+func (m Model) renderRow(r row, now time.Time) string {
+	t := r.task
+
+	check := "[ ]"
+	if t.Done {
+		check = "[✓]"
+	}
+
+	name := t.Name
+	if t.Done {
+		name = doneStyle.Render(name)
+	}
+
+	line := fmt.Sprintf("%s%s #%d %s", strings.Repeat("   ", r.depth), check, t.ID, name)
+
+	if t.Priority != domain.PriorityNone {
+		line += " !" + t.Priority.String()
+	}
+	if t.Due != nil {
+		due := "due " + dateparse.Format(*t.Due)
+		if t.IsOverdue(now) {
+			due = overdueStyle.Render(due)
+		}
+		line += " " + due
+	}
+	for _, tag := range t.Tags {
+		if strings.HasPrefix(tag, "@") {
+			line += " " + tag
+		} else {
+			line += " +" + tag
+		}
+	}
+	if r.total > 0 {
+		line += fmt.Sprintf(" [%d/%d]", r.done, r.total)
+	}
+	if r.blocked {
+		line += " [blocked]"
+	}
+	if t.Running() {
+		line += " ⏱"
+	}
+
+	return line
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+	"todo-cli/internal/repository"
+	"todo-cli/internal/usecase"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func press(m tea.Model, keys ...string) tea.Model {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func TestModel_Session(t *testing.T) {
+	repo := &repository.JSONRepository{Filename: filepath.Join(t.TempDir(), "tasks.json")}
+	u := usecase.NewTaskUsecase(repo)
+
+	var m tea.Model = New(u, "tasks")
+	m = press(m, "a", "W", "r", "i", "t", "e", "enter")
+	m = press(m, "a", "T", "e", "s", "t", "enter")
+	m = press(m, "A", "U", "n", "i", "t", "enter")
+
+	// Cursor is on the new subtask; toggle it, then move Test above Write.
+	m = press(m, " ", "k", "K")
+
+	tasks, err := u.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %v", tasks)
+	}
+	if tasks[0].Name != "Test" || tasks[1].Name != "Write" {
+		t.Fatalf("expected Test moved first, got %v", tasks)
+	}
+	if unit := tasks[2]; unit.ParentID != 2 || !unit.Done {
+		t.Fatalf("expected done subtask of #2, got %+v", unit)
+	}
+
+	// Filtering hides Write; deleting Test takes its subtask with it.
+	m = press(m, "/", "t", "e", "s", "enter")
+	if rows := m.(Model).rows; len(rows) != 1 {
+		t.Fatalf("expected 1 filtered row, got %d", len(rows))
+	}
+	m = press(m, "d", "y")
+
+	tasks, err = u.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].Name != "Write" {
+		t.Fatalf("expected only Write left, got %v", tasks)
+	}
+	if err := m.(Model).err; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
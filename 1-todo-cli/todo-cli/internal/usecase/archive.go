@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+	"time"
+	"todo-cli/internal/domain"
+)
+
+var ErrNoArchive = errors.New("archive is not enabled")
+
+// Archive moves done tasks completed before the given time from the list
+// to the archive in one change; a zero time archives all done tasks.
+// A task stays in the list while any of its subtasks does.
+func (u *TaskUsecase) Archive(before time.Time) ([]domain.Task, error) {
+	if u.archive == nil {
+		return nil, ErrNoArchive
+	}
+
+	var moved []domain.Task
+
+	_, err := u.update("archive", func(tasks []domain.Task) ([]domain.Task, error) {
+		old := func(t domain.Task) bool {
+			return t.Done && (before.IsZero() || t.CompletedAt == nil || t.CompletedAt.Before(before))
+		}
+
+		archive := make(map[int]bool)
+		for _, t := range tasks {
+			if !old(t) {
+				continue
+			}
+			archive[t.ID] = true
+			for _, d := range domain.Descendants(tasks, t.ID) {
+				if i := indexOf(tasks, d); !old(tasks[i]) {
+					delete(archive, t.ID)
+					break
+				}
+			}
+		}
+
+		var kept []domain.Task
+		for _, t := range tasks {
+			if archive[t.ID] {
+				moved = append(moved, t)
+			} else {
+				kept = append(kept, t)
+			}
+		}
+
+		if len(moved) == 0 {
+			return tasks, nil
+		}
+		// The archive is written first: if saving the list fails, the
+		// tasks are in both places rather than lost.
+		if err := u.archive.Append(moved); err != nil {
+			return nil, err
+		}
+		return kept, nil
+	})
+
+	return moved, err
+}
+
+// load reads the list, followed by the archived tasks when archived is
+// set. Archived tasks that are back in the list, e.g. after an undo,
+// are shown only once.
+func (u *TaskUsecase) load(archived bool) ([]domain.Task, error) {
+	tasks, err := u.repo.Load()
+	if err != nil || !archived || u.archive == nil {
+		return tasks, err
+	}
+
+	old, err := u.archive.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks = slices.Clip(tasks)
+	for _, t := range old {
+		if indexOf(tasks, t.ID) < 0 {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
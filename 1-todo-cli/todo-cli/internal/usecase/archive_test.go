@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	"todo-cli/internal/domain"
+)
+
+type mockArchive struct {
+	tasks []domain.Task
+}
+
+func (m *mockArchive) Load() ([]domain.Task, error) {
+	return m.tasks, nil
+}
+
+func (m *mockArchive) Append(tasks []domain.Task) error {
+	m.tasks = append(m.tasks, tasks...)
+	return nil
+}
+
+func TestArchive(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "Old", Done: true, CompletedAt: day(1)},
+			{ID: 2, Name: "Recent", Done: true, CompletedAt: day(13)},
+			{ID: 3, Name: "Open"},
+			{ID: 4, Name: "Parent", Done: true, CompletedAt: day(1)},
+			{ID: 5, Name: "Open child", ParentID: 4},
+		},
+	}
+	archive := &mockArchive{}
+	u := NewTaskUsecase(mockRepo, WithArchive(archive))
+
+	moved, err := u.Archive(*day(10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(moved) != 1 || moved[0].ID != 1 || len(archive.tasks) != 1 {
+		t.Fatalf("expected only #1 archived, got %v", moved)
+	}
+
+	all, err := u.Query(ListFilter{Archived: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []int
+	for _, task := range all {
+		ids = append(ids, task.ID)
+	}
+	if expected := []int{2, 3, 4, 5, 1}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+
+	if _, err := NewTaskUsecase(mockRepo).Archive(time.Time{}); !errors.Is(err, ErrNoArchive) {
+		t.Fatalf("expected ErrNoArchive, got %v", err)
+	}
+}
+
+func TestStats(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	ago := func(days int) *time.Time {
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "A", CreatedAt: ago(4)},
+			{ID: 2, Name: "B", CreatedAt: ago(2), Due: ago(1)},
+			{ID: 3, Name: "C", Done: true, CompletedAt: ago(1)},
+		},
+	}
+	archive := &mockArchive{tasks: []domain.Task{
+		{ID: 4, Name: "D", Done: true, CompletedAt: ago(7)},
+		{ID: 5, Name: "E", Done: true, CompletedAt: ago(30)},
+	}}
+	u := NewTaskUsecase(mockRepo, WithArchive(archive))
+	u.now = func() time.Time { return now }
+
+	stats, err := u.Stats(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Total != 5 || stats.Open != 2 || stats.Done != 3 || stats.Overdue != 1 {
+		t.Fatalf("unexpected counts %+v", stats)
+	}
+	if stats.CompletionRate != 0.6 || stats.AverageOpenAge != 3 {
+		t.Fatalf("unexpected rate or age %+v", stats)
+	}
+
+	expected := []WeekCount{
+		{Week: "2026-W41", Start: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), Done: 1},
+		{Week: "2026-W42", Start: time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), Done: 1},
+	}
+	if !reflect.DeepEqual(stats.Weekly, expected) {
+		t.Fatalf("expected %v, got %v", expected, stats.Weekly)
+	}
+}
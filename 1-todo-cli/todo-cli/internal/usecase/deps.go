@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"todo-cli/internal/domain"
+)
+
+var ErrCycle = fmt.Errorf("%w: dependency cycle", domain.ErrConflict)
+
+// Block records that task id cannot start until task on is done.
+// Dependencies that would make a task wait for itself are refused.
+func (u *TaskUsecase) Block(id, on int) (domain.Task, error) {
+	var task domain.Task
+
+	if id == on {
+		return task, fmt.Errorf("%w: #%d cannot wait for itself", domain.ErrInvalidInput, id)
+	}
+
+	saved, err := u.update("block", func(tasks []domain.Task) ([]domain.Task, error) {
+		i := indexOf(tasks, id)
+		if i < 0 {
+			return nil, notFound(id)
+		}
+		if indexOf(tasks, on) < 0 {
+			return nil, notFound(on)
+		}
+		if domain.DependsOn(tasks, on, id) {
+			return nil, fmt.Errorf("%w: #%d already waits for #%d", ErrCycle, on, id)
+		}
+
+		if !slices.Contains(tasks[i].BlockedBy, on) {
+			tasks[i].BlockedBy = append(slices.Clone(tasks[i].BlockedBy), on)
+		}
+		task = tasks[i]
+		return tasks, nil
+	})
+	refresh(saved, &task)
+
+	return task, err
+}
+
+// Unblock removes the dependency of task id on task on.
+func (u *TaskUsecase) Unblock(id, on int) (domain.Task, error) {
+	var task domain.Task
+
+	saved, err := u.update("unblock", func(tasks []domain.Task) ([]domain.Task, error) {
+		i := indexOf(tasks, id)
+		if i < 0 {
+			return nil, notFound(id)
+		}
+
+		blockedBy := slices.DeleteFunc(slices.Clone(tasks[i].BlockedBy), func(b int) bool { return b == on })
+		if len(blockedBy) == 0 {
+			blockedBy = nil
+		}
+		tasks[i].BlockedBy = blockedBy
+		task = tasks[i]
+		return tasks, nil
+	})
+	refresh(saved, &task)
+
+	return task, err
+}
+
+// Next returns up to limit actionable tasks, those not done and not
+// waiting for open tasks, by priority and then due date. A limit of
+// zero returns all of them.
+func (u *TaskUsecase) Next(limit int) ([]domain.Task, error) {
+	tasks, err := u.repo.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	blockers := domain.OpenBlockers(tasks)
+
+	var next []domain.Task
+	for _, t := range tasks {
+		if !t.Done && len(blockers[t.ID]) == 0 {
+			next = append(next, t)
+		}
+	}
+
+	sort.SliceStable(next, func(i, j int) bool {
+		a, b := next[i], next[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.Due == nil || b.Due == nil {
+			return a.Due != nil && b.Due == nil
+		}
+		return a.Due.Before(*b.Due)
+	})
+
+	if limit > 0 && len(next) > limit {
+		next = next[:limit]
+	}
+	return next, nil
+}
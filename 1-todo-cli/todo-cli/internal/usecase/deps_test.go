@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	"todo-cli/internal/domain"
+)
+
+func TestBlock_Cycle(t *testing.T) {
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}, {ID: 3, Name: "C"}},
+	}
+	u := NewTaskUsecase(mockRepo)
+
+	if _, err := u.Block(2, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := u.Block(3, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := u.Block(1, 3); !errors.Is(err, ErrCycle) {
+		t.Fatalf("expected ErrCycle, got %v", err)
+	}
+	if _, err := u.Block(1, 1); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+	if _, err := u.Block(1, 9); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// Deleting a blocker drops the dependency on it.
+	if _, err := u.Delete([]int{2}, ChildrenRefuse); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if blockedBy := mockRepo.tasks[1].BlockedBy; blockedBy != nil {
+		t.Fatalf("expected #3 to wait for nothing, got %v", blockedBy)
+	}
+}
+
+func TestNext(t *testing.T) {
+	soon := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "Design", Done: true},
+			{ID: 2, Name: "Build", Priority: domain.PriorityHigh, BlockedBy: []int{1}},
+			{ID: 3, Name: "Ship", Priority: domain.PriorityHigh, BlockedBy: []int{2}},
+			{ID: 4, Name: "Email", Due: &soon},
+			{ID: 5, Name: "Tidy"},
+			{ID: 6, Name: "Plan", Priority: domain.PriorityLow},
+		},
+	}
+	u := NewTaskUsecase(mockRepo)
+
+	next, err := u.Next(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []int
+	for _, task := range next {
+		ids = append(ids, task.ID)
+	}
+	if expected := []int{2, 6, 4, 5}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+
+	if next, _ := u.Next(1); len(next) != 1 {
+		t.Fatalf("expected the limit to apply, got %v", next)
+	}
+}
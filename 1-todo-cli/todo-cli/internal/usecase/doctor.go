@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"todo-cli/internal/domain"
+)
+
+// untitled names tasks found without a name.
+const untitled = "(untitled)"
+
+// Problem is an inconsistency found in a task list: what is wrong with
+// the task that had ID when checked, and how Check repairs it.
+type Problem struct {
+	ID      int    `json:"id"`
+	Problem string `json:"problem"`
+	Fix     string `json:"fix"`
+}
+
+// Check validates the task list: IDs must be positive and unique, tasks
+// need a name, and parent and dependency links must point to other
+// existing tasks without forming a loop. With fix set the problems are
+// repaired and the list saved, which also stores it in the current
+// layout. The repair is journaled as one operation, unless tasks shared
+// an ID: the journal tells tasks apart by ID, so it cannot record that
+// repair, and is cleared as its operations no longer apply.
+func (u *TaskUsecase) Check(fix bool) ([]Problem, error) {
+	var problems []Problem
+
+	err := u.locked(func() error {
+		tasks, err := u.repo.Load()
+		if err != nil {
+			return err
+		}
+		before, err := snapshot(tasks)
+		if err != nil {
+			return err
+		}
+		id, err := u.repo.NextID()
+		if err != nil {
+			return err
+		}
+
+		var fixed []domain.Task
+		fixed, problems = diagnose(slices.Clone(tasks), id)
+		if !fix {
+			return nil
+		}
+
+		if len(before) == len(tasks) {
+			return u.commit("doctor", before, fixed)
+		}
+
+		if err := touch(before, fixed, u.now()); err != nil {
+			return err
+		}
+		if err := u.repo.Save(fixed); err != nil {
+			return err
+		}
+		if u.journal == nil {
+			return nil
+		}
+		return u.journal.Save(domain.Journal{})
+	})
+
+	return problems, err
+}
+
+func diagnose(tasks []domain.Task, nextID int) ([]domain.Task, []Problem) {
+	var problems []Problem
+	report := func(id int, problem, fix string, args ...any) {
+		problems = append(problems, Problem{ID: id, Problem: problem, Fix: fmt.Sprintf(fix, args...)})
+	}
+
+	// Tasks sharing an ID keep it in file order; links to the ID
+	// stay with the first of them.
+	seen := make(map[int]bool, len(tasks))
+	for i := range tasks {
+		t := &tasks[i]
+		switch {
+		case t.ID <= 0:
+			report(t.ID, "invalid ID", "moved to #%d", nextID)
+		case seen[t.ID]:
+			report(t.ID, "duplicate ID", "moved to #%d", nextID)
+		default:
+			seen[t.ID] = true
+			continue
+		}
+		t.ID = nextID
+		seen[t.ID] = true
+		nextID++
+	}
+
+	for i := range tasks {
+		t := &tasks[i]
+
+		if strings.TrimSpace(t.Name) == "" {
+			report(t.ID, "missing name", "named %q", untitled)
+			t.Name = untitled
+		}
+
+		if t.ParentID != 0 && !seen[t.ParentID] {
+			report(t.ID, fmt.Sprintf("parent #%d does not exist", t.ParentID), "made top-level")
+			t.ParentID = 0
+		}
+
+		var blockedBy []int
+		for _, b := range t.BlockedBy {
+			switch {
+			case b == t.ID:
+				report(t.ID, "blocked by itself", "dependency removed")
+			case !seen[b]:
+				report(t.ID, fmt.Sprintf("blocked by missing task #%d", b), "dependency removed")
+			case slices.Contains(blockedBy, b):
+				report(t.ID, fmt.Sprintf("blocked by #%d twice", b), "duplicate removed")
+			default:
+				blockedBy = append(blockedBy, b)
+			}
+		}
+		t.BlockedBy = blockedBy
+	}
+
+	// Loops are cut at the task where they are found.
+	for i := range tasks {
+		t := &tasks[i]
+		for p, steps := t.ParentID, 0; p != 0 && steps < len(tasks); steps++ {
+			if p == t.ID {
+				report(t.ID, "subtask of itself", "made top-level")
+				t.ParentID = 0
+				break
+			}
+			p = parentOf(tasks, p)
+		}
+
+		for _, b := range slices.Clone(t.BlockedBy) {
+			if domain.DependsOn(tasks, b, t.ID) {
+				report(t.ID, fmt.Sprintf("dependency loop through #%d", b), "dependency removed")
+				t.BlockedBy = slices.DeleteFunc(t.BlockedBy, func(x int) bool { return x == b })
+			}
+		}
+		if len(t.BlockedBy) == 0 {
+			t.BlockedBy = nil
+		}
+	}
+
+	return tasks, problems
+}
+
+func parentOf(tasks []domain.Task, id int) int {
+	if i := indexOf(tasks, id); i >= 0 {
+		return tasks[i].ParentID
+	}
+	return 0
+}
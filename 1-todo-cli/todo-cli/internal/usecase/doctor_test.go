@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"todo-cli/internal/domain"
+)
+
+func TestCheck(t *testing.T) {
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "Write", BlockedBy: []int{2, 9}},
+			{ID: 2, Name: "Test", ParentID: 3, BlockedBy: []int{1}},
+			{ID: 3, Name: "Ship", ParentID: 2},
+			{ID: 2, Name: ""},
+			{ID: 4, Name: "Docs", ParentID: 7},
+		},
+		nextID: 5,
+	}
+	u := NewTaskUsecase(mockRepo)
+
+	problems, err := u.Check(false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mockRepo.saves != 0 {
+		t.Fatal("check without fix saved")
+	}
+
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Problem+": "+p.Fix)
+	}
+	want := []string{
+		"duplicate ID: moved to #5",
+		"blocked by missing task #9: dependency removed",
+		"missing name: named \"(untitled)\"",
+		"parent #7 does not exist: made top-level",
+		"dependency loop through #2: dependency removed",
+		"subtask of itself: made top-level",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected problems\n%q\ngot\n%q", want, got)
+	}
+
+	if _, err := u.Check(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if problems, _ := u.Check(false); len(problems) != 0 {
+		t.Fatalf("expected no problems after fix, got %+v", problems)
+	}
+	if task := mockRepo.tasks[3]; task.ID != 5 || task.Name != "(untitled)" {
+		t.Fatalf("unexpected repaired task %+v", task)
+	}
+}
+
+func TestCheck_FixIsJournaled(t *testing.T) {
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "Write", BlockedBy: []int{9}},
+			{ID: 2, Name: ""},
+		},
+		nextID: 3,
+	}
+	original := append([]domain.Task(nil), mockRepo.tasks...)
+	u := NewTaskUsecase(mockRepo, WithJournal(&mockJournal{}))
+
+	if _, err := u.Check(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, task := range mockRepo.tasks {
+		if task.UpdatedAt == nil {
+			t.Fatalf("expected repaired task to be stamped, got %+v", task)
+		}
+	}
+
+	op, err := u.Undo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if op.Name != "doctor" {
+		t.Fatalf("expected doctor to be undone, got %s", op.Name)
+	}
+	if !reflect.DeepEqual(mockRepo.tasks, original) {
+		t.Fatalf("expected %v restored, got %v", original, mockRepo.tasks)
+	}
+}
+
+func TestCheck_FixDuplicateIDsClearsJournal(t *testing.T) {
+	mockRepo := &mockRepository{}
+	journal := &mockJournal{}
+	u := NewTaskUsecase(mockRepo, WithJournal(journal))
+
+	if _, err := u.Add("A"); err != nil {
+		t.Fatal(err)
+	}
+	mockRepo.tasks = append(mockRepo.tasks, domain.Task{ID: 1, Name: "B"})
+
+	if _, err := u.Check(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(journal.journal.Operations) != 0 {
+		t.Fatalf("expected the journal cleared, got %+v", journal.journal)
+	}
+	if _, err := u.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("expected ErrNothingToUndo, got %v", err)
+	}
+	if task := mockRepo.tasks[1]; task.ID != 2 || task.UpdatedAt == nil {
+		t.Fatalf("unexpected repaired task %+v", task)
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"slices"
+	"todo-cli/internal/domain"
+)
+
+// ImportResult reports the tasks an import added and those it skipped
+// because a task with the same name already exists.
+type ImportResult struct {
+	Added   []domain.Task `json:"added"`
+	Skipped []domain.Task `json:"skipped"`
+}
+
+// Import appends incoming tasks under fresh IDs in a single locked cycle.
+// With dryRun set nothing is saved; the result shows what would be added.
+func (u *TaskUsecase) Import(incoming []domain.Task, dryRun bool) (ImportResult, error) {
+	var result ImportResult
+
+	if dryRun {
+		tasks, err := u.repo.Load()
+		if err != nil {
+			return result, err
+		}
+		id, err := u.repo.NextID()
+		if err != nil {
+			return result, err
+		}
+		_, result = u.planImport(tasks, incoming, id)
+		return result, nil
+	}
+
+	saved, err := u.update("import", func(tasks []domain.Task) ([]domain.Task, error) {
+		id, err := u.repo.NextID()
+		if err != nil {
+			return nil, err
+		}
+		tasks, result = u.planImport(tasks, incoming, id)
+		return tasks, nil
+	})
+	refreshAll(saved, result.Added)
+
+	return result, err
+}
+
+func (u *TaskUsecase) planImport(tasks, incoming []domain.Task, nextID int) ([]domain.Task, ImportResult) {
+	var result ImportResult
+
+	names := make(map[string]int, len(tasks))
+	for _, t := range tasks {
+		names[t.Name] = t.ID
+	}
+
+	// Imported IDs are replaced, so parent and dependency links are
+	// remapped to the new IDs, or to the existing task a skipped one
+	// duplicates. Links to tasks outside the import are dropped.
+	idMap := make(map[int]int)
+	start := len(tasks)
+
+	now := u.now()
+	for _, t := range incoming {
+		if existing, ok := names[t.Name]; ok {
+			if t.ID != 0 {
+				idMap[t.ID] = existing
+			}
+			result.Skipped = append(result.Skipped, t)
+			continue
+		}
+		names[t.Name] = nextID
+
+		if t.ID != 0 {
+			idMap[t.ID] = nextID
+		}
+		t.ID = nextID
+		nextID++
+		if t.CreatedAt == nil {
+			t.CreatedAt = &now
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	for i := start; i < len(tasks); i++ {
+		tasks[i].ParentID = idMap[tasks[i].ParentID]
+
+		var blockedBy []int
+		for _, id := range tasks[i].BlockedBy {
+			if mapped := idMap[id]; mapped != 0 {
+				blockedBy = append(blockedBy, mapped)
+			}
+		}
+		tasks[i].BlockedBy = blockedBy
+	}
+
+	// Parent chains and dependencies that loop back to the task are
+	// cut there, as doctor does.
+	for i := start; i < len(tasks); i++ {
+		for p, steps := tasks[i].ParentID, 0; p != 0 && steps < len(tasks); steps++ {
+			if p == tasks[i].ID {
+				tasks[i].ParentID = 0
+				break
+			}
+			p = parentOf(tasks, p)
+		}
+
+		for _, b := range slices.Clone(tasks[i].BlockedBy) {
+			if b == tasks[i].ID || domain.DependsOn(tasks, b, tasks[i].ID) {
+				tasks[i].BlockedBy = slices.DeleteFunc(tasks[i].BlockedBy, func(x int) bool { return x == b })
+			}
+		}
+		if len(tasks[i].BlockedBy) == 0 {
+			tasks[i].BlockedBy = nil
+		}
+		result.Added = append(result.Added, tasks[i])
+	}
+
+	return tasks, result
+}
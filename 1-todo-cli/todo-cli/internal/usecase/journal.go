@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"todo-cli/internal/domain"
+)
+
+// maxJournalOperations bounds how far back undo can go.
+const maxJournalOperations = 100
+
+var (
+	ErrNoJournal     = errors.New("history is not enabled")
+	ErrNothingToUndo = fmt.Errorf("%w: nothing to undo", domain.ErrConflict)
+	ErrNothingToRedo = fmt.Errorf("%w: nothing to redo", domain.ErrConflict)
+
+	// ErrHistoryConflict means tasks an operation touched were changed
+	// outside the history since, so undoing or redoing it would
+	// overwrite those changes.
+	ErrHistoryConflict = fmt.Errorf("%w: tasks were changed outside the history", domain.ErrConflict)
+)
+
+// History returns the journal; operations from Cursor on are undone.
+func (u *TaskUsecase) History() (domain.Journal, error) {
+	if u.journal == nil {
+		return domain.Journal{}, ErrNoJournal
+	}
+	return u.journal.Load()
+}
+
+// Undo reverts the most recent applied operation and returns it.
+func (u *TaskUsecase) Undo() (domain.Operation, error) {
+	return u.step(false)
+}
+
+// Redo re-applies the most recently undone operation and returns it.
+func (u *TaskUsecase) Redo() (domain.Operation, error) {
+	return u.step(true)
+}
+
+func (u *TaskUsecase) step(forward bool) (domain.Operation, error) {
+	if u.journal == nil {
+		return domain.Operation{}, ErrNoJournal
+	}
+
+	var op domain.Operation
+	err := u.locked(func() error {
+		journal, err := u.journal.Load()
+		if err != nil {
+			return err
+		}
+
+		var idx int
+		if forward {
+			if journal.Cursor >= len(journal.Operations) {
+				return ErrNothingToRedo
+			}
+			idx = journal.Cursor
+			journal.Cursor++
+		} else {
+			if journal.Cursor == 0 {
+				return ErrNothingToUndo
+			}
+			journal.Cursor--
+			idx = journal.Cursor
+		}
+		op = journal.Operations[idx]
+
+		tasks, err := u.repo.Load()
+		if err != nil {
+			return err
+		}
+
+		if err := checkChanges(tasks, op.Changes, forward); err != nil {
+			return err
+		}
+		tasks = applyChanges(tasks, op.Changes, forward)
+		if op.OrderAfter != nil {
+			if forward {
+				tasks = reorder(tasks, op.OrderAfter)
+			} else {
+				tasks = reorder(tasks, op.OrderBefore)
+			}
+		}
+
+		if err := u.repo.Save(tasks); err != nil {
+			return err
+		}
+
+		return u.journal.Save(journal)
+	})
+
+	return op, err
+}
+
+// record appends an operation to the journal, discarding any redo tail.
+// Operations that changed nothing are not recorded.
+func (u *TaskUsecase) record(name string, before map[int]taskSnapshot, after []domain.Task) error {
+	if u.journal == nil {
+		return nil
+	}
+
+	changes, err := diff(before, after)
+	if err != nil {
+		return err
+	}
+
+	op := domain.Operation{
+		Name:    name,
+		Time:    u.now(),
+		Changes: changes,
+	}
+	if orderBefore, orderAfter := order(before, after); rearranged(orderBefore, orderAfter) {
+		op.OrderBefore, op.OrderAfter = orderBefore, orderAfter
+	}
+
+	if len(op.Changes) == 0 && op.OrderAfter == nil {
+		return nil
+	}
+
+	journal, err := u.journal.Load()
+	if err != nil {
+		return err
+	}
+
+	journal.Operations = append(journal.Operations[:journal.Cursor], op)
+	if n := len(journal.Operations); n > maxJournalOperations {
+		journal.Operations = journal.Operations[n-maxJournalOperations:]
+	}
+	journal.Cursor = len(journal.Operations)
+
+	return u.journal.Save(journal)
+}
+
+// taskSnapshot is an encoded copy of a task taken before modification,
+// so in-place edits by the modify function cannot alter it.
+type taskSnapshot struct {
+	index int
+	data  []byte
+}
+
+func snapshot(tasks []domain.Task) (map[int]taskSnapshot, error) {
+	snap := make(map[int]taskSnapshot, len(tasks))
+	for i, t := range tasks {
+		data, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		snap[t.ID] = taskSnapshot{index: i, data: data}
+	}
+	return snap, nil
+}
+
+func (s taskSnapshot) task() (*domain.Task, error) {
+	var t domain.Task
+	if err := json.Unmarshal(s.data, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// diff lists the tasks that were deleted, added or modified.
+func diff(before map[int]taskSnapshot, after []domain.Task) ([]domain.Change, error) {
+	var changes []domain.Change
+	seen := make(map[int]bool, len(after))
+
+	for i := range after {
+		t := after[i]
+		seen[t.ID] = true
+
+		prev, existed := before[t.ID]
+		if !existed {
+			changes = append(changes, domain.Change{After: &t, Index: i})
+			continue
+		}
+
+		data, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(data, prev.data) {
+			continue
+		}
+
+		old, err := prev.task()
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, domain.Change{Before: old, After: &t, Index: i})
+	}
+
+	var deleted []domain.Change
+	for id, prev := range before {
+		if seen[id] {
+			continue
+		}
+		old, err := prev.task()
+		if err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, domain.Change{Before: old, Index: prev.index})
+	}
+	slices.SortFunc(deleted, func(a, b domain.Change) int { return a.Index - b.Index })
+
+	return append(changes, deleted...), nil
+}
+
+// checkChanges verifies that the tasks changes apply to are still on
+// the side they are moved from: the After side when undoing, the Before
+// side when redoing. A task to be re-added must not exist.
+func checkChanges(tasks []domain.Task, changes []domain.Change, forward bool) error {
+	current := make(map[int]domain.Task, len(tasks))
+	for _, t := range tasks {
+		current[t.ID] = t
+	}
+
+	for _, c := range changes {
+		from, to := c.Before, c.After
+		if !forward {
+			from, to = to, from
+		}
+
+		if from == nil {
+			if _, ok := current[to.ID]; ok {
+				return fmt.Errorf("%w (#%d exists again)", ErrHistoryConflict, to.ID)
+			}
+			continue
+		}
+
+		t, ok := current[from.ID]
+		if !ok {
+			return fmt.Errorf("%w (#%d no longer exists)", ErrHistoryConflict, from.ID)
+		}
+		want, err := json.Marshal(from)
+		if err != nil {
+			return err
+		}
+		got, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(got, want) {
+			return fmt.Errorf("%w (#%d was modified)", ErrHistoryConflict, from.ID)
+		}
+	}
+	return nil
+}
+
+// applyChanges moves tasks to the After side of changes when forward
+// is true and back to the Before side otherwise. Removals happen first
+// and insertions in ascending index order so positions are restored.
+func applyChanges(tasks []domain.Task, changes []domain.Change, forward bool) []domain.Task {
+	type insert struct {
+		task  domain.Task
+		index int
+	}
+
+	var inserts []insert
+	remove := make(map[int]bool)
+	replace := make(map[int]domain.Task)
+
+	for _, c := range changes {
+		from, to := c.Before, c.After
+		if !forward {
+			from, to = to, from
+		}
+
+		switch {
+		case to == nil:
+			remove[from.ID] = true
+		case from == nil:
+			inserts = append(inserts, insert{task: *to, index: c.Index})
+		default:
+			replace[to.ID] = *to
+		}
+	}
+
+	var result []domain.Task
+	for _, t := range tasks {
+		if remove[t.ID] {
+			continue
+		}
+		if r, ok := replace[t.ID]; ok {
+			t = r
+		}
+		result = append(result, t)
+	}
+
+	slices.SortFunc(inserts, func(a, b insert) int { return a.index - b.index })
+	for _, in := range inserts {
+		idx := min(max(in.index, 0), len(result))
+		result = slices.Insert(result, idx, in.task)
+	}
+
+	return result
+}
+
+// order returns the task IDs in list order before and after an operation.
+func order(before map[int]taskSnapshot, after []domain.Task) ([]int, []int) {
+	orderBefore := make([]int, len(before))
+	for id, snap := range before {
+		orderBefore[snap.index] = id
+	}
+
+	orderAfter := make([]int, len(after))
+	for i, t := range after {
+		orderAfter[i] = t.ID
+	}
+
+	return orderBefore, orderAfter
+}
+
+// rearranged reports whether the tasks present on both sides
+// appear in a different relative order.
+func rearranged(before, after []int) bool {
+	inAfter := make(map[int]bool, len(after))
+	for _, id := range after {
+		inAfter[id] = true
+	}
+	inBefore := make(map[int]bool, len(before))
+	for _, id := range before {
+		inBefore[id] = true
+	}
+
+	var a, b []int
+	for _, id := range before {
+		if inAfter[id] {
+			a = append(a, id)
+		}
+	}
+	for _, id := range after {
+		if inBefore[id] {
+			b = append(b, id)
+		}
+	}
+	return !slices.Equal(a, b)
+}
+
+// reorder sorts tasks into the given ID order; tasks missing
+// from it keep their relative order at the end.
+func reorder(tasks []domain.Task, ids []int) []domain.Task {
+	pos := make(map[int]int, len(ids))
+	for i, id := range ids {
+		pos[id] = i
+	}
+
+	rank := func(t domain.Task) int {
+		if p, ok := pos[t.ID]; ok {
+			return p
+		}
+		return len(ids)
+	}
+
+	slices.SortStableFunc(tasks, func(a, b domain.Task) int { return rank(a) - rank(b) })
+	return tasks
+}
+
+// touch sets UpdatedAt on the tasks that were added or changed
+// since the snapshot was taken.
+func touch(before map[int]taskSnapshot, tasks []domain.Task, now time.Time) error {
+	for i := range tasks {
+		prev, existed := before[tasks[i].ID]
+		if existed {
+			data, err := json.Marshal(tasks[i])
+			if err != nil {
+				return err
+			}
+			if bytes.Equal(data, prev.data) {
+				continue
+			}
+		}
+		tasks[i].UpdatedAt = &now
+	}
+	return nil
+}
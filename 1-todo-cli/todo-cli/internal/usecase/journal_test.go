@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"todo-cli/internal/domain"
+)
+
+type mockJournal struct {
+	journal domain.Journal
+}
+
+func (m *mockJournal) Load() (domain.Journal, error) {
+	return m.journal, nil
+}
+
+func (m *mockJournal) Save(j domain.Journal) error {
+	m.journal = j
+	return nil
+}
+
+func TestUndoRedo_Delete(t *testing.T) {
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "A"},
+			{ID: 2, Name: "B"},
+			{ID: 3, Name: "C"},
+		},
+	}
+	original := append([]domain.Task(nil), mockRepo.tasks...)
+
+	u := NewTaskUsecase(mockRepo, WithJournal(&mockJournal{}))
+
+	if _, err := u.Delete([]int{2}, ChildrenRefuse); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	op, err := u.Undo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if op.Name != "delete" {
+		t.Fatalf("expected delete to be undone, got %s", op.Name)
+	}
+	if !reflect.DeepEqual(mockRepo.tasks, original) {
+		t.Fatalf("expected %v restored in place, got %v", original, mockRepo.tasks)
+	}
+
+	if _, err := u.Redo(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []domain.Task{{ID: 1, Name: "A"}, {ID: 3, Name: "C"}}
+	if !reflect.DeepEqual(mockRepo.tasks, expected) {
+		t.Fatalf("expected %v, got %v", expected, mockRepo.tasks)
+	}
+}
+
+func TestUndo_Sequence(t *testing.T) {
+	mockRepo := &mockRepository{}
+	u := NewTaskUsecase(mockRepo, WithJournal(&mockJournal{}))
+
+	if _, err := u.Add("A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.MarkDone([]int{1}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := u.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if mockRepo.tasks[0].Done {
+		t.Fatal("expected done to be undone")
+	}
+
+	if _, err := u.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if len(mockRepo.tasks) != 0 {
+		t.Fatalf("expected add to be undone, got %v", mockRepo.tasks)
+	}
+
+	if _, err := u.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("expected ErrNothingToUndo, got %v", err)
+	}
+}
+
+func TestRecord_DiscardsRedoTail(t *testing.T) {
+	journal := &mockJournal{}
+	u := NewTaskUsecase(&mockRepository{}, WithJournal(journal))
+
+	if _, err := u.Add("A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Add("B"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := u.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Fatalf("expected ErrNothingToRedo, got %v", err)
+	}
+	if n := len(journal.journal.Operations); n != 1 {
+		t.Fatalf("expected 1 operation, got %d", n)
+	}
+}
+
+func TestUpdate_FailedOperationNotRecorded(t *testing.T) {
+	journal := &mockJournal{}
+	u := NewTaskUsecase(&mockRepository{}, WithJournal(journal))
+
+	if _, err := u.Delete([]int{1}, ChildrenRefuse); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if n := len(journal.journal.Operations); n != 0 {
+		t.Fatalf("expected no operations, got %d", n)
+	}
+}
+
+func TestUndoRedo_Move(t *testing.T) {
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "A"},
+			{ID: 2, Name: "B"},
+		},
+	}
+	journal := &mockJournal{}
+	u := NewTaskUsecase(mockRepo, WithJournal(journal))
+
+	if err := u.Move(2, -1); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(journal.journal.Operations); n != 1 {
+		t.Fatalf("expected the reorder to be recorded, got %d operations", n)
+	}
+
+	if _, err := u.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if mockRepo.tasks[0].ID != 1 {
+		t.Fatalf("expected original order, got %v", mockRepo.tasks)
+	}
+
+	if _, err := u.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if mockRepo.tasks[0].ID != 2 {
+		t.Fatalf("expected moved order, got %v", mockRepo.tasks)
+	}
+}
+
+func TestUndoRedo_ChangedOutsideHistory(t *testing.T) {
+	mockRepo := &mockRepository{}
+	journal := &mockJournal{}
+	u := NewTaskUsecase(mockRepo, WithJournal(journal))
+
+	if _, err := u.Add("A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Rename(1, "B"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Edited by hand: undoing the rename would overwrite it.
+	mockRepo.tasks[0].Name = "C"
+	if _, err := u.Undo(); !errors.Is(err, ErrHistoryConflict) {
+		t.Fatalf("expected ErrHistoryConflict, got %v", err)
+	}
+	if mockRepo.tasks[0].Name != "C" || journal.journal.Cursor != 2 {
+		t.Fatalf("expected nothing changed, got %v at cursor %d", mockRepo.tasks, journal.journal.Cursor)
+	}
+
+	mockRepo.tasks[0].Name = "B"
+	if _, err := u.Undo(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleted by hand: redoing the rename would bring it back.
+	mockRepo.tasks = nil
+	if _, err := u.Redo(); !errors.Is(err, ErrHistoryConflict) {
+		t.Fatalf("expected ErrHistoryConflict, got %v", err)
+	}
+	if len(mockRepo.tasks) != 0 {
+		t.Fatalf("expected nothing changed, got %v", mockRepo.tasks)
+	}
+}
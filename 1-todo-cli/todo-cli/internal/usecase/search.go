@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/fuzzy"
+)
+
+// SearchResult is a task found by Search. Positions are the rune
+// indexes of the task name that matched, for highlighting.
+type SearchResult struct {
+	Task      domain.Task `json:"task"`
+	Score     int         `json:"score"`
+	Positions []int       `json:"positions,omitempty"`
+}
+
+// Search ranks the tasks that fuzzy-match every word of query, best first.
+// A word may match the name, a tag or the note; tag and note matches
+// count half.
+func (u *TaskUsecase) Search(query string) ([]SearchResult, error) {
+	tasks, err := u.repo.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	var results []SearchResult
+	for _, t := range tasks {
+		if r, ok := match(t, words); ok {
+			results = append(results, r)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results, nil
+}
+
+func match(t domain.Task, words []string) (SearchResult, bool) {
+	result := SearchResult{Task: t}
+	seen := map[int]bool{}
+
+	for _, w := range words {
+		score, positions, ok := fuzzy.Match(w, t.Name)
+
+		for _, tag := range t.Tags {
+			if s, _, tagOK := fuzzy.Match(w, strings.TrimPrefix(tag, "@")); tagOK && (!ok || s/2 > score) {
+				score, positions, ok = s/2, nil, true
+			}
+		}
+
+		if s, _, noteOK := fuzzy.Match(w, t.Note); noteOK && (!ok || s/2 > score) {
+			score, positions, ok = s/2, nil, true
+		}
+
+		if !ok {
+			return SearchResult{}, false
+		}
+
+		result.Score += score
+		for _, p := range positions {
+			if !seen[p] {
+				seen[p] = true
+				result.Positions = append(result.Positions, p)
+			}
+		}
+	}
+
+	sort.Ints(result.Positions)
+	return result, true
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"time"
+	"todo-cli/internal/dateparse"
+)
+
+// Stats summarizes a list together with its archive.
+type Stats struct {
+	Total          int         `json:"total"`
+	Open           int         `json:"open"`
+	Done           int         `json:"done"`
+	Overdue        int         `json:"overdue"`
+	CompletionRate float64     `json:"completion_rate"`
+	AverageOpenAge float64     `json:"average_open_age_days"`
+	Weekly         []WeekCount `json:"weekly"`
+}
+
+// WeekCount is the number of tasks completed in the week starting Monday Start.
+type WeekCount struct {
+	Week  string    `json:"week"`
+	Start time.Time `json:"start"`
+	Done  int       `json:"done"`
+}
+
+// Stats computes completion figures over the list and the archive,
+// with the throughput of the given number of weeks up to this one.
+// The open age is averaged over open tasks that record when they were created.
+func (u *TaskUsecase) Stats(weeks int) (Stats, error) {
+	var stats Stats
+
+	tasks, err := u.load(true)
+	if err != nil {
+		return stats, err
+	}
+
+	now := u.now()
+	today := dateparse.Day(now)
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+
+	stats.Weekly = make([]WeekCount, weeks)
+	for i := range stats.Weekly {
+		start := monday.AddDate(0, 0, -7*(weeks-1-i))
+		year, week := start.ISOWeek()
+		stats.Weekly[i] = WeekCount{Week: fmt.Sprintf("%d-W%02d", year, week), Start: start}
+	}
+	first := monday.AddDate(0, 0, -7*(weeks-1))
+
+	var age time.Duration
+	var aged int
+
+	for _, t := range tasks {
+		stats.Total++
+		if !t.Done {
+			stats.Open++
+			if t.IsOverdue(now) {
+				stats.Overdue++
+			}
+			if t.CreatedAt != nil {
+				age += now.Sub(*t.CreatedAt)
+				aged++
+			}
+			continue
+		}
+
+		stats.Done++
+		if t.CompletedAt == nil || t.CompletedAt.Before(first) {
+			continue
+		}
+		days := math.Round(dateparse.Day(t.CompletedAt.In(now.Location())).Sub(first).Hours() / 24)
+		if i := int(days) / 7; i < weeks {
+			stats.Weekly[i].Done++
+		}
+	}
+
+	if stats.Total > 0 {
+		stats.CompletionRate = float64(stats.Done) / float64(stats.Total)
+	}
+	if aged > 0 {
+		stats.AverageOpenAge = age.Hours() / 24 / float64(aged)
+	}
+
+	return stats, nil
+}
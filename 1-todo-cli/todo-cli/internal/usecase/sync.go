@@ -0,0 +1,488 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"sort"
+	"time"
+	"todo-cli/internal/domain"
+	"todo-cli/internal/repository"
+)
+
+// Side is one of the two copies of a list being synced.
+type Side int
+
+const (
+	SideLocal Side = iota
+	SideOther
+)
+
+func (s Side) String() string {
+	if s == SideOther {
+		return "other"
+	}
+	return "local"
+}
+
+func (s Side) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Conflict is a task changed differently on both sides since the last
+// sync. Fields are the JSON fields both sides changed; when one side
+// deleted the task its copy is nil and Fields is empty. Kept is the side
+// whose version won.
+type Conflict struct {
+	ID     int          `json:"id"`
+	Fields []string     `json:"fields,omitempty"`
+	Local  *domain.Task `json:"local"`
+	Other  *domain.Task `json:"other"`
+	Kept   Side         `json:"kept"`
+}
+
+// Resolver picks the side whose version wins a conflict.
+type Resolver func(Conflict) (Side, error)
+
+// Newer is the default Resolver. The more recently updated version wins
+// an edit conflict, local on a tie; an edited task always wins over its
+// deletion, so no change is lost without asking.
+func Newer(c Conflict) (Side, error) {
+	switch {
+	case c.Local == nil:
+		return SideOther, nil
+	case c.Other == nil:
+		return SideLocal, nil
+	case updated(*c.Other).After(updated(*c.Local)):
+		return SideOther, nil
+	}
+	return SideLocal, nil
+}
+
+func updated(t domain.Task) time.Time {
+	switch {
+	case t.UpdatedAt != nil:
+		return *t.UpdatedAt
+	case t.CreatedAt != nil:
+		return *t.CreatedAt
+	}
+	return time.Time{}
+}
+
+// SyncResult lists the changes a sync makes to each side and the
+// conflicts it settled. Renumbered maps the IDs of tasks added on the
+// other side to their new IDs where the same ID was added on both sides.
+type SyncResult struct {
+	Local      []domain.Change `json:"local"`
+	Other      []domain.Change `json:"other"`
+	Conflicts  []Conflict      `json:"conflicts"`
+	Renumbered map[int]int     `json:"renumbered,omitempty"`
+}
+
+// Sync merges the list with another copy of it, e.g. one kept in a
+// synced folder, and saves the result to both. base holds the tasks as
+// of the last sync between the two: changes made on one side since then
+// are taken over, and tasks changed on both sides are merged field by
+// field. Fields changed differently on both sides are settled by resolve,
+// or by Newer if it is nil. base starts out empty and is replaced by the
+// result. A task not in base with the same ID and creation time on both
+// sides is one task copied before the first sync; its versions are merged
+// without a base, so every field they differ in is a conflict.
+// With dryRun set nothing is saved.
+func (u *TaskUsecase) Sync(other, base repository.TaskRepository, resolve Resolver, dryRun bool) (SyncResult, error) {
+	var result SyncResult
+	if resolve == nil {
+		resolve = Newer
+	}
+
+	err := u.locked(func() (err error) {
+		unlock, err := other.Lock()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if uerr := unlock(); err == nil {
+				err = uerr
+			}
+		}()
+
+		local, err := u.repo.Load()
+		if err != nil {
+			return err
+		}
+		remote, err := other.Load()
+		if err != nil {
+			return err
+		}
+		common, err := base.Load()
+		if err != nil {
+			return err
+		}
+
+		localNext, err := u.repo.NextID()
+		if err != nil {
+			return err
+		}
+		otherNext, err := other.NextID()
+		if err != nil {
+			return err
+		}
+
+		merged, err := merge(common, local, remote, max(localNext, otherNext), resolve, &result)
+		if err != nil {
+			return err
+		}
+
+		before, err := snapshot(local)
+		if err != nil {
+			return err
+		}
+		if result.Local, err = syncChanges(before, merged); err != nil {
+			return err
+		}
+		otherBefore, err := snapshot(remote)
+		if err != nil {
+			return err
+		}
+		if result.Other, err = syncChanges(otherBefore, merged); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return nil
+		}
+
+		// The base is written last: if anything fails before, the next
+		// sync still sees the unsaved side's changes as changes.
+		if len(result.Other) > 0 {
+			if err := other.Save(merged); err != nil {
+				return err
+			}
+		}
+		if len(result.Local) > 0 {
+			if err := u.repo.Save(merged); err != nil {
+				return err
+			}
+			if err := u.record("sync", before, merged); err != nil {
+				return err
+			}
+		}
+		return base.Save(merged)
+	})
+
+	return result, err
+}
+
+// syncChanges is diff leaving out tasks whose only change is UpdatedAt,
+// as when the same edit was made on both sides.
+func syncChanges(before map[int]taskSnapshot, after []domain.Task) ([]domain.Change, error) {
+	changes, err := diff(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []domain.Change
+	for _, c := range changes {
+		if c.Before != nil && c.After != nil {
+			same, err := sameTask(*c.Before, *c.After)
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// merge combines local and other against their common base. Tasks keep
+// the local order, followed by tasks only the other side has.
+func merge(base, local, other []domain.Task, nextID int, resolve Resolver, result *SyncResult) ([]domain.Task, error) {
+	baseByID, localByID := byID(base), byID(local)
+
+	// IDs are handed out independently on both sides, so the same ID
+	// may have been added twice. The other side's task gets a new ID.
+	renumbered := map[int]int{}
+	for _, t := range other {
+		if _, ok := baseByID[t.ID]; ok {
+			continue
+		}
+		if l, ok := localByID[t.ID]; ok {
+			same, err := sameTask(l, t)
+			if err != nil {
+				return nil, err
+			}
+			if !same && !sameOrigin(l, t) {
+				renumbered[t.ID] = nextID
+				nextID++
+			}
+		}
+	}
+	if len(renumbered) > 0 {
+		other = renumber(other, renumbered)
+		result.Renumbered = renumbered
+	}
+	otherByID := byID(other)
+
+	ids := make([]int, 0, len(local)+len(other))
+	for _, t := range local {
+		ids = append(ids, t.ID)
+	}
+	for _, t := range other {
+		if _, ok := localByID[t.ID]; !ok {
+			ids = append(ids, t.ID)
+		}
+	}
+
+	var merged []domain.Task
+	for _, id := range ids {
+		b, inBase := baseByID[id]
+		l, inLocal := localByID[id]
+		o, inOther := otherByID[id]
+
+		switch {
+		case !inBase && inLocal && inOther && sameOrigin(l, o):
+			// One task, diverged before the first sync.
+			t, err := mergeTask(nil, l, o, resolve, result)
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, t)
+
+		case !inBase:
+			// Added since the last sync, on one side or identically on both.
+			if !inLocal || inOther && updated(o).After(updated(l)) {
+				l = o
+			}
+			merged = append(merged, l)
+
+		case inLocal && inOther:
+			t, err := mergeTask(&b, l, o, resolve, result)
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, t)
+
+		case inLocal || inOther:
+			// Deleted on one side: the deletion stands unless the
+			// other side changed the task in the meantime.
+			c := Conflict{ID: id}
+			kept, side := l, SideLocal
+			if inLocal {
+				c.Local = &l
+			} else {
+				c.Other = &o
+				kept, side = o, SideOther
+			}
+
+			same, err := sameTask(b, kept)
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+
+			if c.Kept, err = resolve(c); err != nil {
+				return nil, err
+			}
+			result.Conflicts = append(result.Conflicts, c)
+			if c.Kept == side {
+				merged = append(merged, kept)
+			}
+		}
+	}
+
+	return repair(merged), nil
+}
+
+// mergeTask merges the two versions of a task field by field. A field
+// changed on one side only takes that change; fields changed differently
+// on both sides are a conflict for resolve. Without a base every field
+// the versions differ in is a conflict.
+func mergeTask(base *domain.Task, local, other domain.Task, resolve Resolver, result *SyncResult) (domain.Task, error) {
+	var bf map[string]json.RawMessage
+	if base != nil {
+		var err error
+		if bf, err = fields(*base); err != nil {
+			return domain.Task{}, err
+		}
+	}
+	lf, err := fields(local)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	of, err := fields(other)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	keys := map[string]bool{}
+	for _, f := range []map[string]json.RawMessage{bf, lf, of} {
+		for k := range f {
+			keys[k] = true
+		}
+	}
+
+	merged := map[string]json.RawMessage{}
+	set := func(k string, v json.RawMessage) {
+		if v != nil {
+			merged[k] = v
+		}
+	}
+
+	var conflicting []string
+	for k := range keys {
+		b, l, o := bf[k], lf[k], of[k]
+		switch {
+		case bytes.Equal(l, o), base != nil && bytes.Equal(b, o):
+			set(k, l)
+		case base != nil && bytes.Equal(b, l):
+			set(k, o)
+		default:
+			conflicting = append(conflicting, k)
+			set(k, l)
+		}
+	}
+
+	if len(conflicting) > 0 {
+		sort.Strings(conflicting)
+		c := Conflict{ID: local.ID, Fields: conflicting, Local: &local, Other: &other}
+		if c.Kept, err = resolve(c); err != nil {
+			return domain.Task{}, err
+		}
+		result.Conflicts = append(result.Conflicts, c)
+
+		if c.Kept == SideOther {
+			for _, k := range conflicting {
+				delete(merged, k)
+				set(k, of[k])
+			}
+		}
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	var t domain.Task
+	if err := json.Unmarshal(data, &t); err != nil {
+		return domain.Task{}, err
+	}
+
+	t.UpdatedAt = local.UpdatedAt
+	if updated(other).After(updated(local)) {
+		t.UpdatedAt = other.UpdatedAt
+	}
+	return t, nil
+}
+
+// sameOrigin reports whether two tasks with the same ID were created at
+// the same time, and so are copies of one task.
+func sameOrigin(a, b domain.Task) bool {
+	return a.CreatedAt != nil && b.CreatedAt != nil && a.CreatedAt.Equal(*b.CreatedAt)
+}
+
+// fields encodes a task as its JSON fields, leaving out UpdatedAt,
+// which changes with every edit and is not merged itself.
+func fields(t domain.Task) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var f map[string]json.RawMessage
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	delete(f, "updated_at")
+	return f, nil
+}
+
+// sameTask reports whether two versions of a task differ only in UpdatedAt.
+func sameTask(a, b domain.Task) (bool, error) {
+	a.UpdatedAt, b.UpdatedAt = nil, nil
+	da, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	db, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(da, db), nil
+}
+
+func byID(tasks []domain.Task) map[int]domain.Task {
+	m := make(map[int]domain.Task, len(tasks))
+	for _, t := range tasks {
+		m[t.ID] = t
+	}
+	return m
+}
+
+// renumber moves tasks to new IDs and updates the links to them.
+func renumber(tasks []domain.Task, ids map[int]int) []domain.Task {
+	moved := make([]domain.Task, len(tasks))
+	for i, t := range tasks {
+		if id, ok := ids[t.ID]; ok {
+			t.ID = id
+		}
+		if id, ok := ids[t.ParentID]; ok {
+			t.ParentID = id
+		}
+		if t.BlockedBy != nil {
+			blockedBy := make([]int, len(t.BlockedBy))
+			for j, b := range t.BlockedBy {
+				if id, ok := ids[b]; ok {
+					b = id
+				}
+				blockedBy[j] = b
+			}
+			t.BlockedBy = blockedBy
+		}
+		moved[i] = t
+	}
+	return moved
+}
+
+// repair drops links that a merge left dangling: subtasks of deleted
+// tasks become top-level, dependencies on deleted tasks are removed, and
+// parent links that two sides turned into a loop are cut.
+func repair(tasks []domain.Task) []domain.Task {
+	parents := make(map[int]int, len(tasks))
+	for _, t := range tasks {
+		parents[t.ID] = t.ParentID
+	}
+
+	for i := range tasks {
+		t := &tasks[i]
+		if _, ok := parents[t.ParentID]; !ok {
+			t.ParentID = 0
+		}
+		t.BlockedBy = slices.DeleteFunc(slices.Clone(t.BlockedBy), func(b int) bool {
+			_, ok := parents[b]
+			return !ok
+		})
+		if len(t.BlockedBy) == 0 {
+			t.BlockedBy = nil
+		}
+		parents[t.ID] = t.ParentID
+	}
+
+	for i := range tasks {
+		t := &tasks[i]
+		seen := map[int]bool{}
+		for p := t.ParentID; p != 0 && !seen[p]; p = parents[p] {
+			if p == t.ID {
+				t.ParentID = 0
+				parents[t.ID] = 0
+				break
+			}
+			seen[p] = true
+		}
+	}
+
+	return tasks
+}
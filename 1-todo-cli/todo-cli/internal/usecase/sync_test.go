@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"todo-cli/internal/domain"
+)
+
+func TestSync(t *testing.T) {
+	at := func(hour int) *time.Time {
+		tm := time.Date(2026, 10, 17, hour, 0, 0, 0, time.UTC)
+		return &tm
+	}
+
+	local := &mockRepository{tasks: []domain.Task{{ID: 1, Name: "Write"}}}
+	other := &mockRepository{tasks: []domain.Task{{ID: 1, Name: "Shop"}, {ID: 2, Name: "Cook", ParentID: 1}}}
+	// The base belongs to the list and is covered by its lock.
+	base := &mockRepository{locked: true}
+	u := NewTaskUsecase(local)
+
+	// First sync: both sides added #1, so the other side's task and
+	// the subtask linked to it move to a new ID.
+	result, err := u.Sync(other, base, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []domain.Task{{ID: 1, Name: "Write"}, {ID: 3, Name: "Shop"}, {ID: 2, Name: "Cook", ParentID: 3}}
+	if !reflect.DeepEqual(local.tasks, want) || !reflect.DeepEqual(other.tasks, want) || !reflect.DeepEqual(base.tasks, want) {
+		t.Fatalf("expected all sides to be %v, got %v / %v / %v", want, local.tasks, other.tasks, base.tasks)
+	}
+	if !reflect.DeepEqual(result.Renumbered, map[int]int{1: 3}) {
+		t.Fatalf("unexpected renumbering: %v", result.Renumbered)
+	}
+
+	// Independent edits on both sides since then.
+	local.tasks = []domain.Task{
+		{ID: 1, Name: "Write docs", UpdatedAt: at(9)},
+		{ID: 3, Name: "Shop food", UpdatedAt: at(11)},
+		{ID: 2, Name: "Cook", ParentID: 3},
+	}
+	other.tasks = []domain.Task{
+		{ID: 1, Name: "Write", Priority: domain.PriorityHigh, UpdatedAt: at(10)},
+		{ID: 3, Name: "Shop drinks", UpdatedAt: at(10)},
+		{ID: 4, Name: "Clean", UpdatedAt: at(10)},
+	}
+
+	result, err = u.Sync(other, base, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want = []domain.Task{
+		{ID: 1, Name: "Write docs", Priority: domain.PriorityHigh, UpdatedAt: at(10)},
+		{ID: 3, Name: "Shop food", UpdatedAt: at(11)},
+		{ID: 4, Name: "Clean", UpdatedAt: at(10)},
+	}
+	if !reflect.DeepEqual(local.tasks, want) || !reflect.DeepEqual(other.tasks, want) {
+		t.Fatalf("expected both sides to be %v, got %v / %v", want, local.tasks, other.tasks)
+	}
+
+	if len(result.Conflicts) != 1 {
+		t.Fatalf("expected one conflict, got %+v", result.Conflicts)
+	}
+	if c := result.Conflicts[0]; c.ID != 3 || !reflect.DeepEqual(c.Fields, []string{"name"}) || c.Kept != SideLocal {
+		t.Fatalf("unexpected conflict %+v", c)
+	}
+}
+
+func TestSync_FirstSyncOfDivergedCopies(t *testing.T) {
+	at := func(hour int) *time.Time {
+		tm := time.Date(2026, 10, 17, hour, 0, 0, 0, time.UTC)
+		return &tm
+	}
+
+	// One list copied to two machines and edited on both before syncing.
+	local := &mockRepository{tasks: []domain.Task{
+		{ID: 1, Name: "Write docs", CreatedAt: at(8), UpdatedAt: at(11)},
+		{ID: 2, Name: "Shop", CreatedAt: at(8)},
+	}}
+	other := &mockRepository{tasks: []domain.Task{
+		{ID: 1, Name: "Write", Priority: domain.PriorityHigh, CreatedAt: at(8), UpdatedAt: at(10)},
+		{ID: 2, Name: "Shop", CreatedAt: at(8)},
+	}}
+	base := &mockRepository{locked: true}
+	u := NewTaskUsecase(local)
+
+	var asked []Conflict
+	resolve := func(c Conflict) (Side, error) {
+		asked = append(asked, c)
+		return SideOther, nil
+	}
+
+	result, err := u.Sync(other, base, resolve, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Renumbered != nil {
+		t.Fatalf("expected no renumbering, got %v", result.Renumbered)
+	}
+	if len(asked) != 1 || asked[0].ID != 1 || !reflect.DeepEqual(asked[0].Fields, []string{"name", "priority"}) {
+		t.Fatalf("expected to be asked about name and priority of #1, got %+v", asked)
+	}
+
+	want := []domain.Task{
+		{ID: 1, Name: "Write", Priority: domain.PriorityHigh, CreatedAt: at(8), UpdatedAt: at(11)},
+		{ID: 2, Name: "Shop", CreatedAt: at(8)},
+	}
+	if !reflect.DeepEqual(local.tasks, want) || !reflect.DeepEqual(base.tasks, want) {
+		t.Fatalf("expected list and base to be %v, got %v / %v", want, local.tasks, base.tasks)
+	}
+	// The other side already had the winning version.
+	if len(result.Other) != 0 {
+		t.Fatalf("expected no changes to the other side, got %+v", result.Other)
+	}
+}
+
+func TestSync_DeleteConflict(t *testing.T) {
+	tasks := []domain.Task{{ID: 1, Name: "Write"}, {ID: 2, Name: "Test"}}
+	local := &mockRepository{tasks: []domain.Task{{ID: 1, Name: "Write"}}}
+	other := &mockRepository{tasks: []domain.Task{{ID: 1, Name: "Write"}, {ID: 2, Name: "Test all"}}}
+	base := &mockRepository{tasks: tasks, locked: true}
+	u := NewTaskUsecase(local)
+
+	var asked Conflict
+	resolve := func(c Conflict) (Side, error) {
+		asked = c
+		return SideLocal, nil
+	}
+
+	result, err := u.Sync(other, base, resolve, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asked.ID != 2 || asked.Local != nil || asked.Other == nil {
+		t.Fatalf("expected to be asked about deleted #2, got %+v", asked)
+	}
+	if len(result.Local) != 0 || len(result.Other) != 1 || result.Other[0].After != nil {
+		t.Fatalf("expected #2 to be deleted from the other side, got %+v", result)
+	}
+
+	// A dry run saves nothing.
+	if local.saves != 0 || other.saves != 0 || base.saves != 0 {
+		t.Fatalf("dry run saved: %d/%d/%d", local.saves, other.saves, base.saves)
+	}
+}
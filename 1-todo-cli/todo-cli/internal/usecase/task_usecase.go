@@ -1,91 +1,614 @@
 package usecase
 
 import (
-	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"todo-cli/internal/dateparse"
 	"todo-cli/internal/domain"
 	"todo-cli/internal/repository"
 )
 
+var (
+	ErrParentNotFound = fmt.Errorf("parent %w", domain.ErrNotFound)
+	ErrHasChildren    = fmt.Errorf("%w: task has subtasks", domain.ErrConflict)
+)
+
 type TaskUsecase struct {
-	repo repository.TaskRepository
+	repo    repository.TaskRepository
+	journal repository.JournalRepository
+	archive repository.ArchiveRepository
+	now     func() time.Time
 }
 
-func NewTaskUsecase(r repository.TaskRepository) *TaskUsecase {
-	return &TaskUsecase{repo: r}
+// Option configures a TaskUsecase.
+type Option func(*TaskUsecase)
+
+// WithJournal records every mutation so it can be undone and redone.
+func WithJournal(j repository.JournalRepository) Option {
+	return func(u *TaskUsecase) {
+		u.journal = j
+	}
 }
 
-func (u *TaskUsecase) Add(name string) error {
-	tasks, err := u.repo.Load()
-	if err != nil {
-		return err
+// WithArchive lets completed tasks be moved out of the list.
+func WithArchive(a repository.ArchiveRepository) Option {
+	return func(u *TaskUsecase) {
+		u.archive = a
 	}
+}
+
+func NewTaskUsecase(r repository.TaskRepository, opts ...Option) *TaskUsecase {
+	u := &TaskUsecase{repo: r, now: time.Now}
 
-	id := 1
-	if len(tasks) > 0 {
-		id = tasks[len(tasks)-1].ID + 1
+	for _, opt := range opts {
+		opt(u)
 	}
+	return u
+}
+
+// AddOption sets optional attributes on a task being added.
+type AddOption func(*domain.Task)
 
-	task := domain.Task{
-		ID:   id,
-		Name: name,
-		Done: false,
+func WithDue(due time.Time) AddOption {
+	return func(t *domain.Task) {
+		t.Due = &due
 	}
+}
+
+func WithPriority(p domain.Priority) AddOption {
+	return func(t *domain.Task) {
+		t.Priority = p
+	}
+}
+
+func WithTags(tags ...string) AddOption {
+	return func(t *domain.Task) {
+		t.Tags = append(t.Tags, tags...)
+	}
+}
 
-	tasks = append(tasks, task)
-	return u.repo.Save(tasks)
+// WithRecurrence makes the task come back after it is completed.
+func WithRecurrence(r domain.Recurrence) AddOption {
+	return func(t *domain.Task) {
+		t.Recurrence = &r
+	}
+}
+
+// WithParent makes the new task a subtask of parentID.
+func WithParent(parentID int) AddOption {
+	return func(t *domain.Task) {
+		t.ParentID = parentID
+	}
+}
+
+func (u *TaskUsecase) Add(name string, opts ...AddOption) (domain.Task, error) {
+	var task domain.Task
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return task, fmt.Errorf("%w: task name is empty", domain.ErrInvalidInput)
+	}
+
+	saved, err := u.update("add", func(tasks []domain.Task) ([]domain.Task, error) {
+		id, err := u.repo.NextID()
+		if err != nil {
+			return nil, err
+		}
+
+		now := u.now()
+		task = domain.Task{
+			ID:        id,
+			Name:      name,
+			Done:      false,
+			CreatedAt: &now,
+		}
+
+		for _, opt := range opts {
+			opt(&task)
+		}
+
+		if task.ParentID != 0 && indexOf(tasks, task.ParentID) < 0 {
+			return nil, fmt.Errorf("%w: #%d", ErrParentNotFound, task.ParentID)
+		}
+
+		// A recurring task without a due date is first due
+		// on the rule's next day, counting today.
+		if task.Recurrence != nil {
+			r := *task.Recurrence
+			if task.Due == nil {
+				r = r.Anchor(now)
+				due := r.First(now)
+				task.Due = &due
+			}
+			r = r.Anchor(*task.Due)
+			task.Recurrence = &r
+		}
+
+		return append(tasks, task), nil
+	})
+	refresh(saved, &task)
+
+	return task, err
 }
 
 func (u *TaskUsecase) List() ([]domain.Task, error) {
 	return u.repo.Load()
 }
 
-func (u *TaskUsecase) Delete(id int) error {
+// Get returns the task with the given ID.
+func (u *TaskUsecase) Get(id int) (domain.Task, error) {
 	tasks, err := u.repo.Load()
 	if err != nil {
-		return err
+		return domain.Task{}, err
+	}
+
+	i := indexOf(tasks, id)
+	if i < 0 {
+		return domain.Task{}, notFound(id)
 	}
+	return tasks[i], nil
+}
+
+// Status selects tasks by completion state.
+type Status int
+
+const (
+	StatusAll Status = iota
+	StatusOpen
+	StatusDone
+)
+
+// SortBy orders listed tasks; SortNone keeps file order.
+type SortBy int
 
-	var updated []domain.Task
-	found := false
+const (
+	SortNone SortBy = iota
+	SortDue
+	SortPriority
+)
 
-	for _, task := range tasks {
-		if task.ID != id {
+// ListFilter narrows and orders the result of Query.
+// Text matches a substring of the name or a tag, ignoring case.
+// Archived adds the archived tasks after those in the list.
+type ListFilter struct {
+	Tag      string
+	Text     string
+	Status   Status
+	Overdue  bool
+	Sort     SortBy
+	Archived bool
+}
+
+// Query lists tasks matching f in the requested order.
+func (u *TaskUsecase) Query(f ListFilter) ([]domain.Task, error) {
+	tasks, err := u.load(f.Archived)
+	if err != nil {
+		return nil, err
+	}
+	return u.filter(tasks, f), nil
+}
+
+// Tree is Query arranged as subtask trees; sorting applies among siblings.
+func (u *TaskUsecase) Tree(f ListFilter) ([]*domain.TaskNode, error) {
+	tasks, err := u.load(f.Archived)
+	if err != nil {
+		return nil, err
+	}
+	return domain.BuildTree(u.filter(tasks, f), tasks), nil
+}
+
+func (u *TaskUsecase) filter(tasks []domain.Task, f ListFilter) []domain.Task {
+	now := u.now()
+	text := strings.ToLower(f.Text)
+
+	var result []domain.Task
+	for _, t := range tasks {
+		if f.Tag != "" && !t.HasTag(f.Tag) {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(t.Name), text) && !t.HasTag(f.Text) {
+			continue
+		}
+		if f.Status == StatusOpen && t.Done || f.Status == StatusDone && !t.Done {
+			continue
+		}
+		if f.Overdue && !t.IsOverdue(now) {
+			continue
+		}
+		result = append(result, t)
+	}
+
+	switch f.Sort {
+	case SortDue:
+		// Tasks without a due date go last.
+		sort.SliceStable(result, func(i, j int) bool {
+			a, b := result[i].Due, result[j].Due
+			if a == nil || b == nil {
+				return a != nil
+			}
+			return a.Before(*b)
+		})
+	case SortPriority:
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].Priority > result[j].Priority
+		})
+	}
+
+	return result
+}
+
+// ChildPolicy decides what happens to the subtasks of a deleted task.
+type ChildPolicy int
+
+const (
+	// ChildrenRefuse fails with ErrHasChildren if the task has subtasks.
+	ChildrenRefuse ChildPolicy = iota
+	// ChildrenDelete deletes the whole subtree.
+	ChildrenDelete
+	// ChildrenPromote moves the subtasks up to the deleted task's parent.
+	ChildrenPromote
+)
+
+// Delete removes the given tasks in one change and returns the removed
+// tasks. Subtasks that are deleted along with their parent do not count
+// as children for the policy.
+func (u *TaskUsecase) Delete(ids []int, children ChildPolicy) ([]domain.Task, error) {
+	var removed []domain.Task
+
+	_, err := u.update("delete", func(tasks []domain.Task) ([]domain.Task, error) {
+		remove, err := idSet(tasks, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			var kept int
+			sub := domain.Descendants(tasks, id)
+			for _, d := range sub {
+				if !remove[d] {
+					kept++
+				}
+			}
+			if kept == 0 {
+				continue
+			}
+
+			switch children {
+			case ChildrenDelete:
+				for _, d := range sub {
+					remove[d] = true
+				}
+			case ChildrenPromote:
+				// handled below
+			default:
+				return nil, fmt.Errorf("%w (#%d has %d)", ErrHasChildren, id, kept)
+			}
+		}
+
+		parents := make(map[int]int, len(tasks))
+		for _, task := range tasks {
+			parents[task.ID] = task.ParentID
+		}
+
+		var updated []domain.Task
+		for _, task := range tasks {
+			if remove[task.ID] {
+				removed = append(removed, task)
+				continue
+			}
+			// Promoted subtasks move up to the nearest kept ancestor.
+			for remove[task.ParentID] {
+				task.ParentID = parents[task.ParentID]
+			}
+			task.BlockedBy = slices.DeleteFunc(slices.Clone(task.BlockedBy), func(b int) bool { return remove[b] })
+			if len(task.BlockedBy) == 0 {
+				task.BlockedBy = nil
+			}
 			updated = append(updated, task)
-		} else {
-			found = true
 		}
+
+		return updated, nil
+	})
+
+	return removed, err
+}
+
+// DoneResult lists the tasks completed by MarkDone and the
+// next occurrences added for completed recurring tasks.
+type DoneResult struct {
+	Completed []domain.Task `json:"completed"`
+	Next      []domain.Task `json:"next,omitempty"`
+}
+
+// MarkDone completes the given tasks in one change and, with cascade
+// set, all of their subtasks. Completing a task stops its timer, and
+// completing a recurring task adds its next occurrence.
+func (u *TaskUsecase) MarkDone(ids []int, cascade bool) (DoneResult, error) {
+	var result DoneResult
+
+	saved, err := u.update("done", func(tasks []domain.Task) ([]domain.Task, error) {
+		done, err := idSet(tasks, ids)
+		if err != nil {
+			return nil, err
+		}
+		if cascade {
+			for _, id := range ids {
+				for _, d := range domain.Descendants(tasks, id) {
+					done[d] = true
+				}
+			}
+		}
+
+		nextID, err := u.repo.NextID()
+		if err != nil {
+			return nil, err
+		}
+
+		now := u.now()
+		for i, task := range tasks {
+			if !done[task.ID] || task.Done {
+				continue
+			}
+
+			tasks[i].Done = true
+			tasks[i].CompletedAt = &now
+			tasks[i].StopTimer(now)
+
+			if task.Recurrence != nil {
+				tasks[i].Recurrence = nil
+				next := nextOccurrence(task, nextID, now)
+				nextID++
+				result.Next = append(result.Next, next)
+			}
+			result.Completed = append(result.Completed, tasks[i])
+		}
+
+		return append(tasks, result.Next...), nil
+	})
+	refreshAll(saved, result.Completed)
+	refreshAll(saved, result.Next)
+
+	return result, err
+}
+
+// nextOccurrence copies a completed recurring task with the next due date.
+// Occurrences missed while the task was overdue are skipped.
+func nextOccurrence(task domain.Task, id int, now time.Time) domain.Task {
+	r := *task.Recurrence
+
+	from := now
+	if task.Due != nil {
+		from = *task.Due
 	}
 
-	if !found {
-		return errors.New("TASK NOT FOUND")
+	due := r.Next(from)
+	today := dateparse.Day(now)
+	for due.Before(today) {
+		due = r.Next(due)
 	}
 
-	// Renumber sequentially
-	for i := range updated {
-		updated[i].ID = i + 1
+	return domain.Task{
+		ID:         id,
+		ParentID:   task.ParentID,
+		Name:       task.Name,
+		Due:        &due,
+		Priority:   task.Priority,
+		Tags:       append([]string(nil), task.Tags...),
+		CreatedAt:  &now,
+		Recurrence: &r,
 	}
+}
 
-	return u.repo.Save(updated)
+// Reopen marks the given tasks as not done in one change and returns
+// those that were done.
+func (u *TaskUsecase) Reopen(ids []int) ([]domain.Task, error) {
+	var reopened []domain.Task
+
+	saved, err := u.update("undone", func(tasks []domain.Task) ([]domain.Task, error) {
+		open, err := idSet(tasks, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, task := range tasks {
+			if !open[task.ID] || !task.Done {
+				continue
+			}
+			tasks[i].Done = false
+			tasks[i].CompletedAt = nil
+			reopened = append(reopened, tasks[i])
+		}
+		return tasks, nil
+	})
+	refreshAll(saved, reopened)
+
+	return reopened, err
 }
 
-func (u *TaskUsecase) MarkDone(id int) error {
-	tasks, err := u.repo.Load()
-	if err != nil {
+// Rename changes the name of a task.
+func (u *TaskUsecase) Rename(id int, name string) (domain.Task, error) {
+	var task domain.Task
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return task, fmt.Errorf("%w: task name is empty", domain.ErrInvalidInput)
+	}
+
+	saved, err := u.update("edit", func(tasks []domain.Task) ([]domain.Task, error) {
+		i := indexOf(tasks, id)
+		if i < 0 {
+			return nil, notFound(id)
+		}
+
+		tasks[i].Name = name
+		task = tasks[i]
+		return tasks, nil
+	})
+	refresh(saved, &task)
+
+	return task, err
+}
+
+// SetNote replaces the note and links of a task. Blank space around the
+// note and each link is trimmed; empty and repeated links are dropped.
+func (u *TaskUsecase) SetNote(id int, note string, links []string) (domain.Task, error) {
+	var task domain.Task
+
+	note = strings.TrimSpace(note)
+
+	var cleaned []string
+	for _, l := range links {
+		if l = strings.TrimSpace(l); l != "" && !slices.Contains(cleaned, l) {
+			cleaned = append(cleaned, l)
+		}
+	}
+
+	saved, err := u.update("note", func(tasks []domain.Task) ([]domain.Task, error) {
+		i := indexOf(tasks, id)
+		if i < 0 {
+			return nil, notFound(id)
+		}
+
+		tasks[i].Note = note
+		tasks[i].Links = cleaned
+		task = tasks[i]
+		return tasks, nil
+	})
+	refresh(saved, &task)
+
+	return task, err
+}
+
+// Move shifts a task offset places among its siblings, the tasks with
+// the same parent; negative offsets move it up. It stops at either end.
+func (u *TaskUsecase) Move(id int, offset int) error {
+	_, err := u.update("move", func(tasks []domain.Task) ([]domain.Task, error) {
+		i := indexOf(tasks, id)
+		if i < 0 {
+			return nil, notFound(id)
+		}
+
+		step := 1
+		if offset < 0 {
+			step = -1
+		}
+
+		for n := 0; n < max(offset, -offset); n++ {
+			j := i + step
+			for j >= 0 && j < len(tasks) && tasks[j].ParentID != tasks[i].ParentID {
+				j += step
+			}
+			if j < 0 || j >= len(tasks) {
+				break
+			}
+			tasks[i], tasks[j] = tasks[j], tasks[i]
+			i = j
+		}
+
+		return tasks, nil
+	})
+	return err
+}
+
+func notFound(id int) error {
+	return fmt.Errorf("%w: #%d", domain.ErrNotFound, id)
+}
+
+// idSet returns ids as a set, or a not-found error for the first
+// ID that is not in tasks.
+func idSet(tasks []domain.Task, ids []int) (map[int]bool, error) {
+	set := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if indexOf(tasks, id) < 0 {
+			return nil, notFound(id)
+		}
+		set[id] = true
+	}
+	return set, nil
+}
+
+func indexOf(tasks []domain.Task, id int) int {
+	for i, t := range tasks {
+		if t.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// update runs one Load/modify/Save cycle while holding the repository
+// lock, so concurrent processes serialize instead of clobbering each other.
+// Nothing is saved when modify returns an error. The resulting changes
+// are recorded in the journal under the given operation name.
+// It returns the tasks as saved, with UpdatedAt set on changed ones.
+func (u *TaskUsecase) update(op string, modify func([]domain.Task) ([]domain.Task, error)) ([]domain.Task, error) {
+	var saved []domain.Task
+	err := u.locked(func() error {
+		tasks, err := u.repo.Load()
+		if err != nil {
+			return err
+		}
+
+		before, err := snapshot(tasks)
+		if err != nil {
+			return err
+		}
+
+		tasks, err = modify(tasks)
+		if err != nil {
+			return err
+		}
+
+		saved = tasks
+		return u.commit(op, before, tasks)
+	})
+	return saved, err
+}
+
+// commit saves tasks, the result of changing the tasks in before, with
+// UpdatedAt set on changed ones, and records the changes in the journal.
+// The caller holds the repository lock.
+func (u *TaskUsecase) commit(op string, before map[int]taskSnapshot, tasks []domain.Task) error {
+	if err := touch(before, tasks, u.now()); err != nil {
+		return err
+	}
+	if err := u.repo.Save(tasks); err != nil {
 		return err
 	}
+	return u.record(op, before, tasks)
+}
 
-	found := false
-	for i, task := range tasks {
-		if task.ID == id {
-			tasks[i].Done = true
-			found = true
-			break
+// refresh replaces each of tasks by the saved task with its ID, for
+// results copied in a modify function before update stamped them.
+func refresh(saved []domain.Task, tasks ...*domain.Task) {
+	for _, t := range tasks {
+		if i := indexOf(saved, t.ID); i >= 0 {
+			*t = saved[i]
 		}
 	}
+}
+
+// refreshAll is refresh for a slice of results.
+func refreshAll(saved, tasks []domain.Task) {
+	for i := range tasks {
+		refresh(saved, &tasks[i])
+	}
+}
 
-	if !found {
-		return errors.New("TASK NOT FOUND")
+// locked runs fn while holding the repository lock.
+func (u *TaskUsecase) locked(fn func() error) (err error) {
+	unlock, err := u.repo.Lock()
+	if err != nil {
+		return err
 	}
+	defer func() {
+		if uerr := unlock(); err == nil {
+			err = uerr
+		}
+	}()
 
-	return u.repo.Save(tasks)
+	return fn()
 }
@@ -4,12 +4,16 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 	"todo-cli/internal/domain"
 )
 
 type mockRepository struct {
-	tasks []domain.Task
-	err   error
+	tasks  []domain.Task
+	nextID int
+	err    error
+	locked bool
+	saves  int
 }
 
 func (m *mockRepository) Load() ([]domain.Task, error) {
@@ -23,10 +27,38 @@ func (m *mockRepository) Save(tasks []domain.Task) error {
 	if m.err != nil {
 		return m.err
 	}
+	if !m.locked {
+		return errors.New("save without lock")
+	}
 	m.tasks = tasks
+	m.saves++
 	return nil
 }
 
+func (m *mockRepository) Lock() (func() error, error) {
+	if m.locked {
+		return nil, errors.New("already locked")
+	}
+	m.locked = true
+	return func() error {
+		m.locked = false
+		return nil
+	}, nil
+}
+
+func (m *mockRepository) NextID() (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	next := max(m.nextID, 1)
+	for _, t := range m.tasks {
+		if t.ID >= next {
+			next = t.ID + 1
+		}
+	}
+	return next, nil
+}
+
 func TestAdd(t *testing.T) {
 	mockRepo := &mockRepository{
 		tasks: []domain.Task{},
@@ -34,7 +66,7 @@ func TestAdd(t *testing.T) {
 
 	u := NewTaskUsecase(mockRepo)
 
-	err := u.Add("Learn Testing")
+	_, err := u.Add("Learn Testing")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -59,13 +91,30 @@ func TestAdd_RepositoryError(t *testing.T) {
 
 	u := NewTaskUsecase(mockRepo)
 
-	err := u.Add("X")
+	_, err := u.Add("X")
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
 }
 
-func TestDelete_Renumber(t *testing.T) {
+func TestAdd_UsesRepositoryNextID(t *testing.T) {
+	mockRepo := &mockRepository{
+		tasks:  []domain.Task{{ID: 1, Name: "A"}},
+		nextID: 5,
+	}
+
+	u := NewTaskUsecase(mockRepo)
+
+	if _, err := u.Add("B"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := mockRepo.tasks[1].ID; got != 5 {
+		t.Fatalf("expected ID 5, got %d", got)
+	}
+}
+
+func TestDelete_KeepsIDs(t *testing.T) {
 	mockRepo := &mockRepository{
 		tasks: []domain.Task{
 			{ID: 1, Name: "A"},
@@ -76,14 +125,14 @@ func TestDelete_Renumber(t *testing.T) {
 
 	u := NewTaskUsecase(mockRepo)
 
-	err := u.Delete(2)
+	_, err := u.Delete([]int{2}, ChildrenRefuse)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	expected := []domain.Task{
 		{ID: 1, Name: "A"},
-		{ID: 2, Name: "C"},
+		{ID: 3, Name: "C"},
 	}
 
 	if !reflect.DeepEqual(mockRepo.tasks, expected) {
@@ -100,10 +149,28 @@ func TestDelete_NotFound(t *testing.T) {
 
 	u := NewTaskUsecase(mockRepo)
 
-	err := u.Delete(99)
-	if err == nil {
+	_, err := u.Delete([]int{99}, ChildrenRefuse)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDelete_NotFoundDoesNotSave(t *testing.T) {
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{{ID: 1, Name: "A"}},
+	}
+
+	u := NewTaskUsecase(mockRepo)
+
+	if _, err := u.Delete([]int{99}, ChildrenRefuse); err == nil {
 		t.Fatal("expected error, got nil")
 	}
+	if mockRepo.saves != 0 {
+		t.Fatalf("expected no save, got %d", mockRepo.saves)
+	}
+	if mockRepo.locked {
+		t.Fatal("expected lock to be released")
+	}
 }
 
 func TestMarkDone(t *testing.T) {
@@ -115,7 +182,7 @@ func TestMarkDone(t *testing.T) {
 
 	u := NewTaskUsecase(mockRepo)
 
-	err := u.MarkDone(1)
+	_, err := u.MarkDone([]int{1}, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -124,3 +191,526 @@ func TestMarkDone(t *testing.T) {
 		t.Fatal("expected task to be marked as done")
 	}
 }
+
+func TestAdd_WithOptions(t *testing.T) {
+	mockRepo := &mockRepository{}
+	u := NewTaskUsecase(mockRepo)
+
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	_, err := u.Add("Deploy", WithDue(due), WithPriority(domain.PriorityHigh), WithTags("ops"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := mockRepo.tasks[0]
+	if got.Due == nil || !got.Due.Equal(due) {
+		t.Fatalf("expected due %v, got %v", due, got.Due)
+	}
+	if got.Priority != domain.PriorityHigh {
+		t.Fatalf("expected high priority, got %v", got.Priority)
+	}
+	if !reflect.DeepEqual(got.Tags, []string{"ops"}) {
+		t.Fatalf("unexpected tags: %v", got.Tags)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "A", Due: day(20), Priority: domain.PriorityLow, Tags: []string{"ops"}},
+			{ID: 2, Name: "B", Due: day(10), Priority: domain.PriorityHigh},
+			{ID: 3, Name: "C", Priority: domain.PriorityMedium, Tags: []string{"Ops"}},
+			{ID: 4, Name: "D", Due: day(5), Done: true},
+		},
+	}
+
+	u := NewTaskUsecase(mockRepo)
+	u.now = func() time.Time { return time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC) }
+
+	ids := func(tasks []domain.Task) []int {
+		var out []int
+		for _, t := range tasks {
+			out = append(out, t.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		filter ListFilter
+		want   []int
+	}{
+		{"all", ListFilter{}, []int{1, 2, 3, 4}},
+		{"tag", ListFilter{Tag: "ops"}, []int{1, 3}},
+		{"open", ListFilter{Status: StatusOpen}, []int{1, 2, 3}},
+		{"done", ListFilter{Status: StatusDone}, []int{4}},
+		{"overdue", ListFilter{Overdue: true}, []int{2}},
+		{"sort due", ListFilter{Sort: SortDue}, []int{4, 2, 1, 3}},
+		{"sort priority", ListFilter{Sort: SortPriority}, []int{2, 3, 1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := u.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(ids(got), tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, ids(got))
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{{ID: 4, Name: "A"}},
+	}
+
+	u := NewTaskUsecase(mockRepo)
+
+	incoming := []domain.Task{{ID: 1, Name: "A"}, {ID: 1, Name: "B"}, {Name: "C"}}
+
+	result, err := u.Import(incoming, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Added) != 2 || len(result.Skipped) != 1 {
+		t.Fatalf("unexpected dry run result: %+v", result)
+	}
+	if len(mockRepo.tasks) != 1 || mockRepo.saves != 0 {
+		t.Fatal("dry run must not save")
+	}
+
+	if _, err := u.Import(incoming, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []int
+	for _, task := range mockRepo.tasks {
+		ids = append(ids, task.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{4, 5, 6}) {
+		t.Fatalf("expected fresh IDs [4 5 6], got %v", ids)
+	}
+}
+
+func TestImport_ParentLoop(t *testing.T) {
+	mockRepo := &mockRepository{}
+	u := NewTaskUsecase(mockRepo, WithArchive(&mockArchive{}))
+
+	incoming := []domain.Task{
+		{ID: 1, Name: "A", ParentID: 2},
+		{ID: 2, Name: "B", ParentID: 1},
+		{ID: 3, Name: "C", ParentID: 3},
+	}
+	if _, err := u.Import(incoming, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mockRepo.tasks[0].ParentID != 0 || mockRepo.tasks[1].ParentID != 1 || mockRepo.tasks[2].ParentID != 0 {
+		t.Fatalf("expected the loops cut, got %+v", mockRepo.tasks)
+	}
+
+	// A loop already in the file, e.g. edited by hand.
+	mockRepo.tasks[0].ParentID = 2
+	roots, err := u.Tree(ListFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(roots) != 3 {
+		t.Fatalf("expected loop members as roots, got %d roots", len(roots))
+	}
+
+	if _, err := u.MarkDone([]int{1}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mockRepo.tasks[1].Done {
+		t.Fatalf("expected #2 done by cascade, got %+v", mockRepo.tasks[1])
+	}
+	if _, err := u.Archive(time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := u.Delete([]int{3}, ChildrenRefuse); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mockRepo.tasks) != 0 {
+		t.Fatalf("expected all tasks gone, got %+v", mockRepo.tasks)
+	}
+}
+
+func TestImport_DependencyLoop(t *testing.T) {
+	mockRepo := &mockRepository{}
+	u := NewTaskUsecase(mockRepo)
+
+	incoming := []domain.Task{
+		{ID: 1, Name: "A", BlockedBy: []int{2}},
+		{ID: 2, Name: "B", BlockedBy: []int{1}},
+		{ID: 3, Name: "C", BlockedBy: []int{3, 1}},
+	}
+	if _, err := u.Import(incoming, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var blockedBy [][]int
+	for _, task := range mockRepo.tasks {
+		blockedBy = append(blockedBy, task.BlockedBy)
+	}
+	if want := [][]int{nil, {1}, {1}}; !reflect.DeepEqual(blockedBy, want) {
+		t.Fatalf("expected dependencies %v, got %v", want, blockedBy)
+	}
+}
+
+func subtaskRepo() *mockRepository {
+	return &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "Release"},
+			{ID: 2, Name: "Tests", ParentID: 1},
+			{ID: 3, Name: "Unit", ParentID: 2},
+			{ID: 4, Name: "Docs", ParentID: 1, Done: true},
+			{ID: 5, Name: "Other"},
+		},
+	}
+}
+
+func TestAdd_WithParent(t *testing.T) {
+	mockRepo := subtaskRepo()
+	u := NewTaskUsecase(mockRepo)
+
+	if _, err := u.Add("Integration", WithParent(2)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := mockRepo.tasks[5].ParentID; got != 2 {
+		t.Fatalf("expected parent 2, got %d", got)
+	}
+
+	if _, err := u.Add("Orphan", WithParent(99)); !errors.Is(err, ErrParentNotFound) {
+		t.Fatalf("expected ErrParentNotFound, got %v", err)
+	}
+}
+
+func TestMarkDone_Cascade(t *testing.T) {
+	mockRepo := subtaskRepo()
+	u := NewTaskUsecase(mockRepo)
+
+	if _, err := u.MarkDone([]int{1}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, task := range mockRepo.tasks {
+		if want := task.ID != 5; task.Done != want {
+			t.Fatalf("task #%d: expected done=%v", task.ID, want)
+		}
+	}
+}
+
+func TestDelete_ChildPolicy(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		policy  ChildPolicy
+		wantErr error
+		want    []domain.Task
+	}{
+		{
+			name:    "refuse",
+			policy:  ChildrenRefuse,
+			wantErr: ErrHasChildren,
+		},
+		{
+			name:   "delete subtree",
+			policy: ChildrenDelete,
+			want:   []domain.Task{{ID: 5, Name: "Other"}},
+		},
+		{
+			name:   "promote",
+			policy: ChildrenPromote,
+			want: []domain.Task{
+				{ID: 2, Name: "Tests", UpdatedAt: &now},
+				{ID: 3, Name: "Unit", ParentID: 2},
+				{ID: 4, Name: "Docs", Done: true, UpdatedAt: &now},
+				{ID: 5, Name: "Other"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := subtaskRepo()
+			u := NewTaskUsecase(mockRepo)
+			u.now = func() time.Time { return now }
+
+			_, err := u.Delete([]int{1}, tt.policy)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, domain.ErrConflict) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(mockRepo.tasks, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, mockRepo.tasks)
+			}
+		})
+	}
+}
+
+func TestTree(t *testing.T) {
+	u := NewTaskUsecase(subtaskRepo())
+
+	roots, err := u.Tree(ListFilter{Status: StatusOpen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(roots) != 2 || roots[0].Task.ID != 1 || roots[1].Task.ID != 5 {
+		t.Fatalf("unexpected roots: %+v", roots)
+	}
+
+	release := roots[0]
+	if release.DoneChildren != 1 || release.TotalChildren != 2 {
+		t.Fatalf("expected 1/2 done children counted from all tasks, got %d/%d",
+			release.DoneChildren, release.TotalChildren)
+	}
+	if len(release.Children) != 1 || release.Children[0].Task.ID != 2 {
+		t.Fatalf("expected only open child #2, got %+v", release.Children)
+	}
+	if len(release.Children[0].Children) != 1 {
+		t.Fatal("expected grandchild #3 under #2")
+	}
+}
+
+func TestMarkDone_SpawnsNextOccurrence(t *testing.T) {
+	weekly, err := domain.ParseRecurrence("weekly:mon")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockRepo := &mockRepository{}
+	u := NewTaskUsecase(mockRepo)
+	// Saturday
+	u.now = func() time.Time { return time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC) }
+
+	if _, err := u.Add("Handover", WithRecurrence(weekly), WithTags("ops")); err != nil {
+		t.Fatal(err)
+	}
+	if due := mockRepo.tasks[0].Due; due == nil || due.Day() != 19 {
+		t.Fatalf("expected first due on Monday 19th, got %v", due)
+	}
+
+	// Completed a week late: the missed Monday is skipped.
+	u.now = func() time.Time { return time.Date(2026, 10, 27, 9, 0, 0, 0, time.UTC) }
+
+	result, err := u.MarkDone([]int{1}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Completed) != 1 || len(result.Next) != 1 || len(mockRepo.tasks) != 2 {
+		t.Fatalf("expected one completed task and one new occurrence, got %+v", result)
+	}
+
+	done, next := mockRepo.tasks[0], mockRepo.tasks[1]
+	if !done.Done || done.Recurrence != nil {
+		t.Fatalf("expected completed task without rule, got %+v", done)
+	}
+	if next.ID != 2 || next.Done || next.Recurrence == nil || next.Due.Day() != 2 || next.Due.Month() != time.November {
+		t.Fatalf("expected open occurrence #2 due Nov 2, got %+v (due %v)", next, next.Due)
+	}
+	if !reflect.DeepEqual(next.Tags, []string{"ops"}) {
+		t.Fatalf("expected tags to carry over, got %v", next.Tags)
+	}
+}
+
+func TestMove(t *testing.T) {
+	mockRepo := subtaskRepo()
+	u := NewTaskUsecase(mockRepo)
+
+	// Docs jumps over Unit, which is not its sibling.
+	if err := u.Move(4, -1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := u.Move(5, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []int
+	for _, task := range mockRepo.tasks {
+		ids = append(ids, task.ID)
+	}
+	if expected := []int{1, 4, 3, 2, 5}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected order %v, got %v", expected, ids)
+	}
+}
+
+func TestReopenAndRename(t *testing.T) {
+	mockRepo := subtaskRepo()
+	u := NewTaskUsecase(mockRepo)
+
+	reopened, err := u.Reopen([]int{4, 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reopened) != 1 || reopened[0].ID != 4 {
+		t.Fatalf("expected only #4 reopened, got %v", reopened)
+	}
+	if task := mockRepo.tasks[3]; task.Done || task.CompletedAt != nil {
+		t.Fatalf("expected #4 to be open, got %+v", task)
+	}
+
+	if _, err := u.Rename(5, "  "); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+	if _, err := u.Rename(9, "X"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if task, err := u.Rename(5, "Chores"); err != nil || task.Name != "Chores" {
+		t.Fatalf("expected rename, got %+v, %v", task, err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "Update deps, reload yarn"},
+			{ID: 2, Name: "Deploy to production", Tags: []string{"ops"}},
+			{ID: 3, Name: "Write release notes", Tags: []string{"deploy"}},
+			{ID: 4, Name: "Buy milk"},
+			{ID: 5, Name: "Call Bob", Note: "Ask about the rollback plan"},
+		},
+	}
+	u := NewTaskUsecase(mockRepo)
+
+	results, err := u.Search("deploy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 3 || results[0].Task.ID != 2 {
+		t.Fatalf("expected 3 results with #2 first, got %v", results)
+	}
+	if !reflect.DeepEqual(results[0].Positions, []int{0, 1, 2, 3, 4, 5}) {
+		t.Fatalf("unexpected positions %v", results[0].Positions)
+	}
+	// A tag match carries no name positions.
+	for _, r := range results {
+		if r.Task.ID == 3 && r.Positions != nil {
+			t.Fatalf("expected no name positions for a tag match, got %v", r.Positions)
+		}
+	}
+
+	if results, _ := u.Search("deploy ops"); len(results) != 1 || results[0].Task.ID != 2 {
+		t.Fatalf("expected every word to match, got %v", results)
+	}
+
+	// A note match carries no name positions either.
+	if results, _ := u.Search("rollback"); len(results) != 1 || results[0].Task.ID != 5 || results[0].Positions != nil {
+		t.Fatalf("expected a note match on #5, got %v", results)
+	}
+}
+
+func TestBulk_IsAtomic(t *testing.T) {
+	mockRepo := subtaskRepo()
+	u := NewTaskUsecase(mockRepo)
+
+	if _, err := u.MarkDone([]int{1, 5, 42}, false); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if mockRepo.saves != 0 || mockRepo.tasks[0].Done {
+		t.Fatal("expected nothing to be saved when one ID is missing")
+	}
+
+	result, err := u.MarkDone([]int{2, 5}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Completed) != 2 || mockRepo.saves != 1 {
+		t.Fatalf("expected 2 tasks completed in one save, got %v in %d", result.Completed, mockRepo.saves)
+	}
+}
+
+func TestDelete_Multiple(t *testing.T) {
+	// Deleting a task together with all of its subtasks needs no policy.
+	mockRepo := subtaskRepo()
+	u := NewTaskUsecase(mockRepo)
+
+	removed, err := u.Delete([]int{2, 3}, ChildrenRefuse)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(removed) != 2 {
+		t.Fatalf("expected 2 removed, got %v", removed)
+	}
+
+	// Promoted subtasks skip over deleted parents.
+	mockRepo = subtaskRepo()
+	u = NewTaskUsecase(mockRepo)
+
+	if _, err := u.Delete([]int{1, 2}, ChildrenPromote); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, task := range mockRepo.tasks {
+		if task.ParentID != 0 {
+			t.Fatalf("expected #%d promoted to the top level, got parent #%d", task.ID, task.ParentID)
+		}
+	}
+}
+
+func TestSetNote(t *testing.T) {
+	mockRepo := subtaskRepo()
+	u := NewTaskUsecase(mockRepo)
+
+	if _, err := u.SetNote(9, "x", nil); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	task, err := u.SetNote(5, "\n  Call Bob first.\n  Then Alice.\n\n", []string{" https://example.com ", "", "docs/plan.md", "https://example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Note != "Call Bob first.\n  Then Alice." {
+		t.Fatalf("unexpected note %q", task.Note)
+	}
+	if want := []string{"https://example.com", "docs/plan.md"}; !reflect.DeepEqual(task.Links, want) {
+		t.Fatalf("expected links %v, got %v", want, task.Links)
+	}
+
+	if task, err = u.SetNote(5, "", nil); err != nil || task.Note != "" || task.Links != nil {
+		t.Fatalf("expected note and links cleared, got %+v, %v", task, err)
+	}
+}
+
+func TestUpdate_ReturnsTasksAsSaved(t *testing.T) {
+	mockRepo := &mockRepository{}
+	u := NewTaskUsecase(mockRepo)
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	u.now = func() time.Time { return now }
+
+	added, err := u.Add("A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	renamed, err := u.Rename(added.ID, "B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := u.MarkDone([]int{added.ID}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, task := range []domain.Task{added, renamed, result.Completed[0]} {
+		if task.UpdatedAt == nil || !task.UpdatedAt.Equal(now) {
+			t.Fatalf("expected UpdatedAt %v, got %+v", now, task)
+		}
+	}
+	if !reflect.DeepEqual(result.Completed[0], mockRepo.tasks[0]) {
+		t.Fatalf("expected %+v as saved, got %+v", mockRepo.tasks[0], result.Completed[0])
+	}
+}
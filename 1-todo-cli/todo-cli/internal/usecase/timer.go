@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/domain"
+)
+
+var (
+	ErrNoTimer      = fmt.Errorf("%w: no timer is running", domain.ErrConflict)
+	ErrTimerRunning = fmt.Errorf("%w: timer is already running", domain.ErrConflict)
+	ErrTaskDone     = fmt.Errorf("%w: task is done", domain.ErrConflict)
+)
+
+// TimerResult is the task a timer was started on and, if another
+// timer had to be stopped for it, that task.
+type TimerResult struct {
+	Started domain.Task  `json:"started"`
+	Stopped *domain.Task `json:"stopped,omitempty"`
+}
+
+// Start tracks time on a task. Only one timer runs at a time, so
+// a timer running on another task is stopped first.
+func (u *TaskUsecase) Start(id int) (TimerResult, error) {
+	var result TimerResult
+
+	saved, err := u.update("start", func(tasks []domain.Task) ([]domain.Task, error) {
+		i := indexOf(tasks, id)
+		if i < 0 {
+			return nil, notFound(id)
+		}
+		if tasks[i].Done {
+			return nil, fmt.Errorf("%w: #%d", ErrTaskDone, id)
+		}
+		if tasks[i].Running() {
+			return nil, fmt.Errorf("%w on #%d", ErrTimerRunning, id)
+		}
+
+		now := u.now()
+		if j := running(tasks); j >= 0 {
+			tasks[j].StopTimer(now)
+			stopped := tasks[j]
+			result.Stopped = &stopped
+		}
+
+		tasks[i].StartTimer(now)
+		result.Started = tasks[i]
+		return tasks, nil
+	})
+	refresh(saved, &result.Started)
+	if result.Stopped != nil {
+		refresh(saved, result.Stopped)
+	}
+
+	return result, err
+}
+
+// Stop stops the running timer and returns its task.
+func (u *TaskUsecase) Stop() (domain.Task, error) {
+	var task domain.Task
+
+	saved, err := u.update("stop", func(tasks []domain.Task) ([]domain.Task, error) {
+		i := running(tasks)
+		if i < 0 {
+			return nil, ErrNoTimer
+		}
+
+		tasks[i].StopTimer(u.now())
+		task = tasks[i]
+		return tasks, nil
+	})
+	refresh(saved, &task)
+
+	return task, err
+}
+
+func running(tasks []domain.Task) int {
+	for i, t := range tasks {
+		if t.Running() {
+			return i
+		}
+	}
+	return -1
+}
+
+// ReportBy groups tracked time in a report.
+type ReportBy int
+
+const (
+	ByTask ReportBy = iota
+	ByTag
+	ByDay
+)
+
+// Report is the time tracked since a given day, grouped into rows.
+type Report struct {
+	Since time.Time   `json:"since"`
+	Rows  []ReportRow `json:"rows"`
+	Total int64       `json:"total_seconds"`
+}
+
+// ReportRow is the time tracked against one task, tag or day.
+type ReportRow struct {
+	Key     string `json:"key"`
+	Seconds int64  `json:"seconds"`
+}
+
+// untagged is the report key for time on tasks without tags.
+const untagged = "(untagged)"
+
+// Report totals the time tracked since the given time. Time on a task
+// with several tags counts toward each of them, so by tag the rows may
+// add up to more than the total. Days are rows from oldest to newest;
+// other rows are ordered by time tracked, most first.
+func (u *TaskUsecase) Report(since time.Time, by ReportBy) (Report, error) {
+	report := Report{Since: since, Rows: []ReportRow{}}
+
+	tasks, err := u.repo.Load()
+	if err != nil {
+		return report, err
+	}
+
+	now := u.now()
+	totals := map[string]time.Duration{}
+	var total time.Duration
+
+	for _, t := range tasks {
+		for _, iv := range t.Time {
+			d := iv.Overlap(since, now)
+			if d == 0 {
+				continue
+			}
+			total += d
+
+			switch by {
+			case ByTask:
+				totals[fmt.Sprintf("#%d %s", t.ID, t.Name)] += d
+			case ByTag:
+				if len(t.Tags) == 0 {
+					totals[untagged] += d
+				}
+				for _, tag := range t.Tags {
+					totals[tag] += d
+				}
+			case ByDay:
+				for day := dateparse.Day(iv.Start); day.Before(now); day = day.AddDate(0, 0, 1) {
+					next := day.AddDate(0, 0, 1)
+					from, to := day, next
+					if from.Before(since) {
+						from = since
+					}
+					if now.Before(to) {
+						to = now
+					}
+					if d := iv.Overlap(from, to); d > 0 {
+						totals[dateparse.Format(day)] += d
+					}
+					if iv.End != nil && !iv.End.After(next) {
+						break
+					}
+				}
+			}
+		}
+	}
+
+	for key, d := range totals {
+		report.Rows = append(report.Rows, ReportRow{Key: key, Seconds: int64(d.Seconds())})
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if by == ByDay || a.Seconds == b.Seconds {
+			return a.Key < b.Key
+		}
+		return a.Seconds > b.Seconds
+	})
+	report.Total = int64(total.Seconds())
+
+	return report, nil
+}
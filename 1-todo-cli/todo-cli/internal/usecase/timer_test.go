@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	"todo-cli/internal/domain"
+)
+
+func TestStartStop(t *testing.T) {
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "Invoice"},
+			{ID: 2, Name: "Review"},
+			{ID: 3, Name: "Shipped", Done: true},
+		},
+	}
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	u := NewTaskUsecase(mockRepo)
+	u.now = func() time.Time { return now }
+
+	if _, err := u.Stop(); !errors.Is(err, ErrNoTimer) {
+		t.Fatalf("expected ErrNoTimer, got %v", err)
+	}
+	if _, err := u.Start(3); !errors.Is(err, ErrTaskDone) {
+		t.Fatalf("expected ErrTaskDone, got %v", err)
+	}
+
+	if _, err := u.Start(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := u.Start(1); !errors.Is(err, ErrTimerRunning) {
+		t.Fatalf("expected ErrTimerRunning, got %v", err)
+	}
+
+	// Starting another task stops the first one.
+	now = now.Add(30 * time.Minute)
+	result, err := u.Start(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Stopped == nil || result.Stopped.ID != 1 || result.Stopped.Tracked(now) != 30*time.Minute {
+		t.Fatalf("expected #1 stopped after 30m, got %+v", result.Stopped)
+	}
+
+	// Completing a task stops its timer.
+	now = now.Add(15 * time.Minute)
+	if _, err := u.MarkDone([]int{2}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mockRepo.tasks[1].Running() || mockRepo.tasks[1].Tracked(now.Add(time.Hour)) != 15*time.Minute {
+		t.Fatalf("expected #2 stopped after 15m, got %+v", mockRepo.tasks[1].Time)
+	}
+}
+
+func TestReport(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 10, day, hour, 0, 0, 0, time.UTC)
+	}
+	end := func(day, hour int) *time.Time {
+		t := at(day, hour)
+		return &t
+	}
+
+	mockRepo := &mockRepository{
+		tasks: []domain.Task{
+			{ID: 1, Name: "Invoice", Tags: []string{"billing", "acme"}, Time: []domain.Interval{
+				{Start: at(10, 9), End: end(10, 11)}, // before the period
+				{Start: at(12, 22), End: end(13, 1)}, // across midnight
+			}},
+			{ID: 2, Name: "Call", Time: []domain.Interval{
+				{Start: at(14, 8)}, // running
+			}},
+		},
+	}
+	u := NewTaskUsecase(mockRepo)
+	u.now = func() time.Time { return at(14, 9) }
+
+	since := at(12, 0)
+	tests := []struct {
+		by   ReportBy
+		want []ReportRow
+	}{
+		{ByTask, []ReportRow{{"#1 Invoice", 3 * 3600}, {"#2 Call", 3600}}},
+		{ByTag, []ReportRow{{"acme", 3 * 3600}, {"billing", 3 * 3600}, {"(untagged)", 3600}}},
+		{ByDay, []ReportRow{{"2026-10-12", 2 * 3600}, {"2026-10-13", 3600}, {"2026-10-14", 3600}}},
+	}
+
+	for _, tt := range tests {
+		report, err := u.Report(since, tt.by)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(report.Rows, tt.want) {
+			t.Errorf("by %d: expected %v, got %v", tt.by, tt.want, report.Rows)
+		}
+		if report.Total != 4*3600 {
+			t.Errorf("by %d: expected 4h total, got %ds", tt.by, report.Total)
+		}
+	}
+}
@@ -12,9 +12,12 @@ import (
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 
+	"notes-api/internal/config"
 	delivery "notes-api/internal/delivery/http"
+	"notes-api/internal/domain"
 	"notes-api/internal/logger"
 	"notes-api/internal/repository/memory"
+	"notes-api/internal/repository/sqlite"
 	"notes-api/internal/usecase"
 )
 
@@ -24,11 +27,35 @@ func main() {
 	logg := logger.New()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("invalid config", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize infrastructure
-	repo := memory.NewMemoryRepository()
+	var repo domain.NoteRepository
+	switch cfg.Storage {
+	case config.StorageSQLite:
+		sqliteRepo, err := sqlite.NewSQLiteRepository(cfg.DBPath)
+		if err != nil {
+			logger.Error("failed to open database", "path", cfg.DBPath, "error", err)
+			os.Exit(1)
+		}
+		defer sqliteRepo.Close()
+		repo = sqliteRepo
+	default:
+		repo = memory.NewMemoryRepository()
+	}
+	logger.Info("storage initialized", "storage", cfg.Storage)
 
 	// Inject into usecase
 	noteUsecase := usecase.NewNoteUsecase(repo)
+	if err := noteUsecase.Reindex(); err != nil {
+		logger.Error("failed to build search index", "error", err)
+		os.Exit(1)
+	}
 
 	// Inject into delivery
 	handler := delivery.NewNoteHandler(noteUsecase, logg)
@@ -47,6 +74,7 @@ func main() {
 
 		r.Post("/", handler.Create)
 		r.Get("/", handler.GetAll)
+		r.Get("/search", handler.Search)
 
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", handler.GetByID)
@@ -57,7 +85,7 @@ func main() {
 
 	// HTTP Server
 	server := &http.Server{
-		Addr:    ":8080",
+		Addr:    cfg.Addr,
 		Handler: r,
 	}
 
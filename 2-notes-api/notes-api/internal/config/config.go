@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// Storage backends selectable with NOTES_STORAGE.
+const (
+	StorageMemory = "memory"
+	StorageSQLite = "sqlite"
+)
+
+// Config holds startup settings read from the environment.
+type Config struct {
+	Addr    string // NOTES_ADDR, default ":8080"
+	Storage string // NOTES_STORAGE, "sqlite" (default) or "memory"
+	DBPath  string // NOTES_DB_PATH, default "notes.db"
+}
+
+// Load reads the configuration from environment variables,
+// falling back to defaults for unset ones.
+func Load() (Config, error) {
+	cfg := Config{
+		Addr:    getenv("NOTES_ADDR", ":8080"),
+		Storage: getenv("NOTES_STORAGE", StorageSQLite),
+		DBPath:  getenv("NOTES_DB_PATH", "notes.db"),
+	}
+
+	switch cfg.Storage {
+	case StorageMemory, StorageSQLite:
+	default:
+		return Config{}, fmt.Errorf("NOTES_STORAGE: unknown storage %q (want %s or %s)",
+			cfg.Storage, StorageMemory, StorageSQLite)
+	}
+
+	return cfg, nil
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,54 @@
+package config
+
+import "testing"
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: Config{Addr: ":8080", Storage: StorageSQLite, DBPath: "notes.db"},
+		},
+		{
+			name: "overrides",
+			env: map[string]string{
+				"NOTES_ADDR":    ":9090",
+				"NOTES_STORAGE": "memory",
+				"NOTES_DB_PATH": "/tmp/x.db",
+			},
+			want: Config{Addr: ":9090", Storage: StorageMemory, DBPath: "/tmp/x.db"},
+		},
+		{
+			name:    "unknown storage",
+			env:     map[string]string{"NOTES_STORAGE": "postgres"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"NOTES_ADDR", "NOTES_STORAGE", "NOTES_DB_PATH"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			got, err := Load()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,36 +1,83 @@
 package dto
 
-import "notes-api/internal/domain"
+import (
+	"time"
 
-// CreateNoteRequest represents incoming create request body
+	"notes-api/internal/domain"
+)
+
+// CreateNoteRequest represents incoming create request body.
+// ID is optional; the server generates one when it is empty.
 type CreateNoteRequest struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
+	ID      string `json:"id,omitempty"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
 }
 
 // UpdateNoteRequest represents update request body.
 type UpdateNoteRequest struct {
-	Title string `json:"title"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
 }
 
 // NoteResponse represents outgoing response body.
 type NoteResponse struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	ContentHTML string    `json:"content_html,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Version     int64     `json:"version"`
+}
+
+// NoteListResponse represents a page of notes.
+type NoteListResponse struct {
+	Notes      []NoteResponse `json:"notes"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Total      int            `json:"total"`
+}
+
+// SearchResultResponse represents a note found by a search.
+// Snippet is HTML with the matching words in <mark>.
+type SearchResultResponse struct {
+	NoteResponse
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+// SearchResponse represents search results, best first.
+type SearchResponse struct {
+	Results []SearchResultResponse `json:"results"`
+	Total   int                    `json:"total"`
 }
 
 // ToDomain converts CreateNoteRequest to domain model.
 func (r CreateNoteRequest) ToDomain() domain.Note {
 	return domain.Note{
-		ID:    r.ID,
-		Title: r.Title,
+		ID:      r.ID,
+		Title:   r.Title,
+		Content: r.Content,
+	}
+}
+
+// ToDomain converts UpdateNoteRequest to domain model.
+func (r UpdateNoteRequest) ToDomain(id string) domain.Note {
+	return domain.Note{
+		ID:      id,
+		Title:   r.Title,
+		Content: r.Content,
 	}
 }
 
 // ToResponse converts domain model to response DTO.
 func ToResponse(n domain.Note) NoteResponse {
 	return NoteResponse{
-		ID:    n.ID,
-		Title: n.Title,
+		ID:        n.ID,
+		Title:     n.Title,
+		Content:   n.Content,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+		Version:   n.Version,
 	}
 }
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	// errPreconditionFailed means an If-Match header names no ETag
+	// any note can have, so the request fails with 412.
+	errPreconditionFailed = errors.New("precondition failed")
+
+	// errMultipleETags means an If-Match header lists several ETags,
+	// which cannot be checked in one atomic step.
+	errMultipleETags = errors.New("If-Match: only one ETag is supported")
+)
+
+// etag is the strong entity tag of a note at version.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseETags splits an If-Match or If-None-Match header into note
+// versions. weak reports whether W/ tags are accepted; tags that are
+// not note versions are skipped, since no note can match them.
+func parseETags(header string, weak bool) (versions []int64, star bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true
+		}
+		if rest, ok := strings.CutPrefix(tag, "W/"); ok {
+			if !weak {
+				continue
+			}
+			tag = rest
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		if v, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64); err == nil && v > 0 {
+			versions = append(versions, v)
+		}
+	}
+	return versions, false
+}
+
+// ifMatchVersion returns the version an If-Match header requires,
+// or 0 if there is no header or it is "*". Weak tags never match.
+func ifMatchVersion(header string) (int64, error) {
+	if strings.TrimSpace(header) == "" {
+		return 0, nil
+	}
+
+	versions, star := parseETags(header, false)
+	switch {
+	case star:
+		return 0, nil
+	case len(versions) == 0:
+		return 0, errPreconditionFailed
+	case len(versions) > 1:
+		return 0, errMultipleETags
+	}
+	return versions[0], nil
+}
+
+// requiredVersion reads the If-Match header of r. If the header is
+// invalid or can never match it responds and returns false.
+func (h *NoteHandler) requiredVersion(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	version, err := ifMatchVersion(r.Header.Get("If-Match"))
+	if err == nil {
+		return version, true
+	}
+
+	status := http.StatusBadRequest
+	if errors.Is(err, errPreconditionFailed) {
+		status = http.StatusPreconditionFailed
+	}
+	h.logger.Warn("invalid_if_match", "if_match", r.Header.Get("If-Match"), "error", err)
+	respondJSON(w, status, map[string]string{"error": err.Error()})
+	return 0, false
+}
+
+// noneMatch reports whether an If-None-Match header matches a note at
+// version, in which case a GET is answered with 304.
+func noneMatch(header string, version int64) bool {
+	if strings.TrimSpace(header) == "" {
+		return false
+	}
+
+	versions, star := parseETags(header, true)
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return star
+}
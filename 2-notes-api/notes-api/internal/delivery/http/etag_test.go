@@ -0,0 +1,56 @@
+package http
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    int64
+		wantErr error
+	}{
+		{header: "", want: 0},
+		{header: "*", want: 0},
+		{header: `"3"`, want: 3},
+		{header: ` "3" `, want: 3},
+		{header: `W/"3"`, wantErr: errPreconditionFailed},
+		{header: `"abc"`, wantErr: errPreconditionFailed},
+		{header: `3`, wantErr: errPreconditionFailed},
+		{header: `"0"`, wantErr: errPreconditionFailed},
+		{header: `"abc", "4"`, want: 4},
+		{header: `"3", "4"`, wantErr: errMultipleETags},
+	}
+
+	for _, tt := range tests {
+		got, err := ifMatchVersion(tt.header)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%q: expected error %v, got %v", tt.header, tt.wantErr, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%q: expected %d, got %d", tt.header, tt.want, got)
+		}
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{header: "", want: false},
+		{header: "*", want: true},
+		{header: `"3"`, want: true},
+		{header: `W/"3"`, want: true},
+		{header: `"2", "3"`, want: true},
+		{header: `"2"`, want: false},
+		{header: `"x"`, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := noneMatch(tt.header, 3); got != tt.want {
+			t.Fatalf("%q: expected %v, got %v", tt.header, tt.want, got)
+		}
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"notes-api/internal/delivery/dto"
 	"notes-api/internal/domain"
 	"notes-api/internal/logger"
+	"notes-api/internal/markdown"
 	"notes-api/internal/usecase"
 
 	"github.com/go-chi/chi/v5"
@@ -37,6 +40,10 @@ func mapErrorToStatus(err error) int {
 		return http.StatusBadRequest
 	case errors.Is(err, domain.ErrNotFound):
 		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
 	default:
 		return http.StatusInternalServerError
 	}
@@ -52,9 +59,8 @@ func (h *NoteHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	note := req.ToDomain()
-
-	if err := h.usecase.Create(note); err != nil {
+	note, err := h.usecase.Create(req.ToDomain())
+	if err != nil {
 		h.logger.Error("failed_create_note", "error", err)
 		respondJSON(w, mapErrorToStatus(err), map[string]string{"error": err.Error()})
 		return
@@ -62,32 +68,137 @@ func (h *NoteHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	resp := dto.ToResponse(note)
 	h.logger.Info("note_created", "note_id", resp.ID)
+	w.Header().Set("ETag", etag(note.Version))
 	respondJSON(w, http.StatusCreated, resp)
 }
 
 // GetAll handles GET /notes
+// Query parameters: limit, cursor, sort (title, created, updated),
+// order (asc, desc) and title_prefix.
 func (h *NoteHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	notes, err := h.usecase.GetAll()
+	opts, err := parseListOptions(r)
+	if err != nil {
+		h.logger.Warn("invalid_list_query", "error", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	result, err := h.usecase.List(opts)
 	if err != nil {
 		h.logger.Error("failed_get_notes", "error", err)
-		respondJSON(w, mapErrorToStatus(err), nil)
+		respondJSON(w, mapErrorToStatus(err), map[string]string{"error": err.Error()})
 		return
 	}
 
-	var responses []dto.NoteResponse
-	for _, n := range notes {
-		responses = append(responses, dto.ToResponse(n))
+	resp := dto.NoteListResponse{
+		Notes:      make([]dto.NoteResponse, 0, len(result.Notes)),
+		NextCursor: result.NextCursor,
+		Total:      result.Total,
+	}
+	for _, n := range result.Notes {
+		resp.Notes = append(resp.Notes, dto.ToResponse(n))
 	}
 
-	h.logger.Info("notes_fetched")
-	respondJSON(w, http.StatusOK, responses)
+	h.logger.Info("notes_fetched", "count", len(resp.Notes), "total", resp.Total)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func parseListOptions(r *http.Request) (usecase.ListOptions, error) {
+	q := r.URL.Query()
+
+	opts := usecase.ListOptions{
+		TitlePrefix: q.Get("title_prefix"),
+		Sort:        domain.SortField(q.Get("sort")),
+		Cursor:      q.Get("cursor"),
+	}
+
+	limit, err := parseLimit(q)
+	if err != nil {
+		return opts, err
+	}
+	opts.Limit = limit
+
+	switch q.Get("order") {
+	case "", "asc":
+	case "desc":
+		opts.Desc = true
+	default:
+		return opts, errors.New("order must be asc or desc")
+	}
+
+	if opts.Sort != "" && !opts.Sort.Valid() {
+		return opts, errors.New("sort must be title, created or updated")
+	}
+
+	return opts, nil
+}
+
+// parseLimit reads the limit query parameter, 0 if absent.
+func parseLimit(q url.Values) (int, error) {
+	s := q.Get("limit")
+	if s == "" {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit < 1 || limit > usecase.MaxLimit {
+		return 0, errors.New("limit must be between 1 and " + strconv.Itoa(usecase.MaxLimit))
+	}
+	return limit, nil
+}
+
+// Search handles GET /notes/search
+// Query parameters: q (required) and limit.
+func (h *NoteHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	limit, err := parseLimit(q)
+	if err != nil {
+		h.logger.Warn("invalid_search_query", "error", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	results, total, err := h.usecase.Search(q.Get("q"), limit)
+	if err != nil {
+		h.logger.Warn("failed_search_notes", "q", q.Get("q"), "error", err)
+		respondJSON(w, mapErrorToStatus(err), map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := dto.SearchResponse{
+		Results: make([]dto.SearchResultResponse, 0, len(results)),
+		Total:   total,
+	}
+	for _, res := range results {
+		resp.Results = append(resp.Results, dto.SearchResultResponse{
+			NoteResponse: dto.ToResponse(res.Note),
+			Score:        res.Score,
+			Snippet:      res.Snippet,
+		})
+	}
+
+	h.logger.Info("notes_searched", "count", len(resp.Results), "total", total)
+	respondJSON(w, http.StatusOK, resp)
 }
 
 // GetByID handles GET /notes/{id}
+// With ?render=html the response also carries the content
+// rendered from Markdown to sanitized HTML. If-None-Match naming
+// the current ETag is answered with 304 Not Modified.
 func (h *NoteHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	// id := strings.TrimPrefix(r.URL.Path, "/notes/")
 
+	render := r.URL.Query().Get("render")
+	if render != "" && render != "html" {
+		h.logger.Warn("invalid_render", "render", render)
+		respondJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "render must be html",
+		})
+		return
+	}
+
 	note, err := h.usecase.GetByID(id)
 	if err != nil {
 		h.logger.Error("failed_get_note", "error", err)
@@ -95,16 +206,37 @@ func (h *NoteHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", etag(note.Version))
+	if noneMatch(r.Header.Get("If-None-Match"), note.Version) {
+		h.logger.Info("note_not_modified", "note_id", id)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	resp := dto.ToResponse(note)
+	if render == "html" {
+		if resp.ContentHTML, err = markdown.ToHTML(note.Content); err != nil {
+			h.logger.Error("failed_render_note", "note_id", id, "error", err)
+			respondJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+	}
 	h.logger.Info("note_fetched", "note_id", resp.ID)
 	respondJSON(w, http.StatusOK, resp)
 }
 
 // Update handles PUT /notes/{id}
+// With If-Match the note is only changed at that ETag,
+// else the response is 412 Precondition Failed.
 func (h *NoteHandler) Update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	// id := strings.TrimPrefix(r.URL.Path, "/notes/")
 
+	version, ok := h.requiredVersion(w, r)
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateNoteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.logger.Warn("invalid_request_body", "error", err)
@@ -114,12 +246,8 @@ func (h *NoteHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	note := domain.Note{
-		ID:    id,
-		Title: req.Title,
-	}
-
-	if err := h.usecase.Update(id, note); err != nil {
+	note, err := h.usecase.Update(id, req.ToDomain(id), version)
+	if err != nil {
 		h.logger.Error("failed_update_note", "error", err)
 		respondJSON(w, mapErrorToStatus(err), map[string]string{
 			"error": err.Error(),
@@ -128,16 +256,23 @@ func (h *NoteHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := dto.ToResponse(note)
-	h.logger.Info("note_updated", "note_id", resp.ID)
+	h.logger.Info("note_updated", "note_id", resp.ID, "version", note.Version)
+	w.Header().Set("ETag", etag(note.Version))
 	respondJSON(w, http.StatusOK, resp)
 }
 
 // Delete handles DELETE /notes/{id}
+// With If-Match the note is only deleted at that ETag.
 func (h *NoteHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	// id := strings.TrimPrefix(r.URL.Path, "/notes/")
 
-	if err := h.usecase.Delete(id); err != nil {
+	version, ok := h.requiredVersion(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Delete(id, version); err != nil {
 		h.logger.Error("failed_delete_note", "error", err)
 		respondJSON(w, mapErrorToStatus(err), map[string]string{
 			"error": err.Error(),
@@ -13,4 +13,12 @@ func TestMapErrorToStatus(t *testing.T) {
 	if mapErrorToStatus(domain.ErrNotFound) != 404 {
 		t.Fatal("wrong status for not found")
 	}
+
+	if mapErrorToStatus(domain.ErrConflict) != 409 {
+		t.Fatal("wrong status for conflict")
+	}
+
+	if mapErrorToStatus(domain.ErrVersionMismatch) != 412 {
+		t.Fatal("wrong status for version mismatch")
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -28,6 +29,7 @@ func setupTestServer() *httptest.Server {
 	r.Route("/notes", func(r chi.Router) {
 		r.Post("/", handler.Create)
 		r.Get("/", handler.GetAll)
+		r.Get("/search", handler.Search)
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", handler.GetByID)
 			r.Put("/", handler.Update)
@@ -157,3 +159,311 @@ func TestNotesIntegration(t *testing.T) {
 		t.Fatalf("expected 404, got %d", resp.StatusCode)
 	}
 }
+
+func TestNotesIntegration_ContentAndRender(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	client := server.Client()
+
+	// Create without ID
+	body, _ := json.Marshal(dto.CreateNoteRequest{
+		Title:   "Markdown",
+		Content: "# Heading\n\n<script>alert(1)</script>",
+	})
+
+	resp, err := client.Post(server.URL+"/notes", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("create request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	var created dto.NoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if created.ID == "" {
+		t.Fatal("expected generated ID")
+	}
+	if created.CreatedAt.IsZero() || !created.UpdatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("expected timestamps, got %+v", created)
+	}
+	if created.ContentHTML != "" {
+		t.Fatal("expected no HTML without render")
+	}
+
+	// Get rendered
+	resp, err = client.Get(server.URL + "/notes/" + created.ID + "?render=html")
+	if err != nil {
+		t.Fatalf("get by id failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var rendered dto.NoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rendered); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if rendered.Content != created.Content {
+		t.Fatalf("expected content %q, got %q", created.Content, rendered.Content)
+	}
+	if !strings.Contains(rendered.ContentHTML, "Heading</h1>") || strings.Contains(rendered.ContentHTML, "<script") {
+		t.Fatalf("unexpected HTML %q", rendered.ContentHTML)
+	}
+
+	// Unknown render format
+	resp, err = client.Get(server.URL + "/notes/" + created.ID + "?render=pdf")
+	if err != nil {
+		t.Fatalf("get by id failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	// Update keeps created_at
+	body, _ = json.Marshal(dto.UpdateNoteRequest{Title: "Markdown", Content: "changed"})
+	req, _ := http.NewRequest(http.MethodPut, server.URL+"/notes/"+created.ID, bytes.NewReader(body))
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	var updated dto.NoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if updated.Content != "changed" || !updated.CreatedAt.Equal(created.CreatedAt) || updated.UpdatedAt.Before(created.UpdatedAt) {
+		t.Fatalf("unexpected update result %+v", updated)
+	}
+
+	// Duplicate ID
+	body, _ = json.Marshal(dto.CreateNoteRequest{ID: created.ID, Title: "Again"})
+	resp, err = client.Post(server.URL+"/notes", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("create request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", resp.StatusCode)
+	}
+}
+
+func TestNotesIntegration_List(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	client := server.Client()
+
+	for _, title := range []string{"delta", "alpha", "echo", "bravo", "charlie", "alpine"} {
+		body, _ := json.Marshal(dto.CreateNoteRequest{Title: title})
+		resp, err := client.Post(server.URL+"/notes", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("create request failed: %v", err)
+		}
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", resp.StatusCode)
+		}
+	}
+
+	list := func(query string) dto.NoteListResponse {
+		t.Helper()
+		resp, err := client.Get(server.URL + "/notes?" + query)
+		if err != nil {
+			t.Fatalf("list failed: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		var page dto.NoteListResponse
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		return page
+	}
+
+	// Page through in title order, descending
+	var titles []string
+	query := "sort=title&order=desc&limit=4"
+	for {
+		page := list(query)
+		if page.Total != 6 {
+			t.Fatalf("expected total 6, got %d", page.Total)
+		}
+		for _, n := range page.Notes {
+			titles = append(titles, n.Title)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query = "sort=title&order=desc&limit=4&cursor=" + page.NextCursor
+	}
+
+	want := "echo delta charlie bravo alpine alpha"
+	if got := strings.Join(titles, " "); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	// Filter by title prefix
+	page := list("sort=title&title_prefix=al")
+	if page.Total != 2 || len(page.Notes) != 2 || page.Notes[0].Title != "alpha" || page.NextCursor != "" {
+		t.Fatalf("unexpected filtered page %+v", page)
+	}
+
+	// Empty result is an empty array
+	resp, err := client.Get(server.URL + "/notes?title_prefix=zulu")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if string(raw["notes"]) != "[]" {
+		t.Fatalf("expected empty array, got %s", raw["notes"])
+	}
+
+	// Invalid parameters
+	for _, q := range []string{"limit=0", "limit=x", "limit=1000", "sort=size", "order=up", "cursor=bogus"} {
+		resp, err := client.Get(server.URL + "/notes?" + q)
+		if err != nil {
+			t.Fatalf("list failed: %v", err)
+		}
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", q, resp.StatusCode)
+		}
+	}
+}
+
+func TestNotesIntegration_ETag(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	client := server.Client()
+
+	do := func(method, path, body string, header map[string]string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	expect := func(resp *http.Response, status int, etag string) {
+		t.Helper()
+		if resp.StatusCode != status {
+			t.Fatalf("expected %d, got %d", status, resp.StatusCode)
+		}
+		if got := resp.Header.Get("ETag"); etag != "" && got != etag {
+			t.Fatalf("expected ETag %s, got %s", etag, got)
+		}
+	}
+
+	expect(do(http.MethodPost, "/notes", `{"id":"1","title":"v1"}`, nil), http.StatusCreated, `"1"`)
+	expect(do(http.MethodGet, "/notes/1", "", nil), http.StatusOK, `"1"`)
+
+	// Conditional GET
+	expect(do(http.MethodGet, "/notes/1", "", map[string]string{"If-None-Match": `"1"`}), http.StatusNotModified, `"1"`)
+	expect(do(http.MethodGet, "/notes/1", "", map[string]string{"If-None-Match": `"9"`}), http.StatusOK, `"1"`)
+
+	// Two clients update from version 1; the second one is refused.
+	ifMatch1 := map[string]string{"If-Match": `"1"`}
+	expect(do(http.MethodPut, "/notes/1", `{"title":"first"}`, ifMatch1), http.StatusOK, `"2"`)
+	expect(do(http.MethodPut, "/notes/1", `{"title":"second"}`, ifMatch1), http.StatusPreconditionFailed, "")
+	expect(do(http.MethodDelete, "/notes/1", "", ifMatch1), http.StatusPreconditionFailed, "")
+
+	resp, err := client.Get(server.URL + "/notes/1")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	var note dto.NoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&note); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if note.Title != "first" || note.Version != 2 {
+		t.Fatalf("expected first at version 2, got %+v", note)
+	}
+
+	// Invalid and unconditional requests
+	expect(do(http.MethodPut, "/notes/1", `{"title":"x"}`, map[string]string{"If-Match": `W/"2"`}), http.StatusPreconditionFailed, "")
+	expect(do(http.MethodPut, "/notes/1", `{"title":"x"}`, map[string]string{"If-Match": `"1", "2"`}), http.StatusBadRequest, "")
+	expect(do(http.MethodPut, "/notes/1", `{"title":"third"}`, nil), http.StatusOK, `"3"`)
+
+	expect(do(http.MethodDelete, "/notes/1", "", map[string]string{"If-Match": `"3"`}), http.StatusOK, "")
+	expect(do(http.MethodGet, "/notes/1", "", nil), http.StatusNotFound, "")
+}
+
+func TestNotesIntegration_Search(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	client := server.Client()
+
+	for _, n := range []dto.CreateNoteRequest{
+		{ID: "1", Title: "Go tips", Content: "Run gofmt and go vet before committing."},
+		{ID: "2", Title: "Groceries", Content: "Milk, eggs and Go-Gurt."},
+		{ID: "3", Title: "Ideas", Content: "Learn Rust."},
+	} {
+		body, _ := json.Marshal(n)
+		resp, err := client.Post(server.URL+"/notes", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("create request failed: %v", err)
+		}
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", resp.StatusCode)
+		}
+	}
+
+	search := func(query string) (int, dto.SearchResponse) {
+		t.Helper()
+		resp, err := client.Get(server.URL + "/notes/search?" + query)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		var result dto.SearchResponse
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result
+	}
+
+	status, result := search("q=go")
+	if status != http.StatusOK || result.Total != 2 || len(result.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d %+v", status, result)
+	}
+	first := result.Results[0]
+	if first.ID != "1" || first.Title != "Go tips" || first.Score <= result.Results[1].Score {
+		t.Fatalf("expected note 1 ranked first, got %+v", result.Results)
+	}
+	if !strings.Contains(first.Snippet, "<mark>gofmt</mark>") {
+		t.Fatalf("expected highlighted snippet, got %q", first.Snippet)
+	}
+
+	// AND with prefix
+	if status, result := search("q=go+mil&limit=5"); status != http.StatusOK || result.Total != 1 || result.Results[0].ID != "2" {
+		t.Fatalf("expected note 2, got %d %+v", status, result)
+	}
+
+	// No match is an empty array
+	resp, err := client.Get(server.URL + "/notes/search?q=python")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if string(raw["results"]) != "[]" {
+		t.Fatalf("expected empty array, got %s", raw["results"])
+	}
+
+	for _, q := range []string{"", "q=", "q=go&limit=0"} {
+		if status, _ := search(q); status != http.StatusBadRequest {
+			t.Fatalf("%q: expected 400, got %d", q, status)
+		}
+	}
+}
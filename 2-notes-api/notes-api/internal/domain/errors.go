@@ -12,6 +12,12 @@ var (
 	//ErrInvalidInput indicates validation failure.
 	ErrInvalidInput = errors.New("invalid input")
 
+	//ErrConflict indicates entity already exists.
+	ErrConflict = errors.New("conflict")
+
+	//ErrVersionMismatch indicates entity changed since the given version.
+	ErrVersionMismatch = errors.New("version mismatch")
+
 	//ErrDb indicates database error.
 	ErrDb = errors.New("db error")
 )
@@ -1,9 +1,16 @@
 package domain
 
+import "time"
+
 // Note is the core business entity.
 // It contains no framework or infrastructure dependency.
 type Note struct {
-	ID      string
-	Title   string
-	Content string
+	ID        string
+	Title     string
+	Content   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Version starts at 1 and is incremented by every update.
+	Version int64
 }
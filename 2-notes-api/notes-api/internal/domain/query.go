@@ -0,0 +1,76 @@
+package domain
+
+import "strings"
+
+// SortField is a note field lists can be ordered by.
+type SortField string
+
+const (
+	SortTitle   SortField = "title"
+	SortCreated SortField = "created"
+	SortUpdated SortField = "updated"
+)
+
+// Valid reports whether f is a known sort field.
+func (f SortField) Valid() bool {
+	switch f {
+	case SortTitle, SortCreated, SortUpdated:
+		return true
+	}
+	return false
+}
+
+// ListQuery selects a page of notes.
+// Notes are ordered by Sort (SortCreated if empty), then by ID
+// so the order is total.
+type ListQuery struct {
+	// TitlePrefix keeps notes whose title starts with it (case-sensitive).
+	TitlePrefix string
+
+	Sort SortField
+	Desc bool
+
+	// After continues a listing: only notes ordered after it are
+	// returned. Only its ID and the Sort field are used.
+	After *Note
+
+	// Limit is the page size; 0 means no limit.
+	Limit int
+}
+
+// NotePage is a result of NoteRepository.List.
+type NotePage struct {
+	Notes []Note
+
+	// Total counts all notes matching the filter, across pages.
+	Total int
+
+	// More reports whether notes follow the last one in Notes.
+	More bool
+}
+
+// Matches reports whether n passes the query's filter.
+func (q ListQuery) Matches(n Note) bool {
+	return strings.HasPrefix(n.Title, q.TitlePrefix)
+}
+
+// Compare returns a negative number if a is listed before b,
+// a positive one if after, and 0 only for notes with the same ID.
+func (q ListQuery) Compare(a, b Note) int {
+	var c int
+	switch q.Sort {
+	case SortTitle:
+		c = strings.Compare(a.Title, b.Title)
+	case SortUpdated:
+		c = a.UpdatedAt.Compare(b.UpdatedAt)
+	default:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c == 0 {
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if q.Desc {
+		c = -c
+	}
+	return c
+}
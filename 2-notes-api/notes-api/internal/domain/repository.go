@@ -5,7 +5,13 @@ package domain
 type NoteRepository interface {
 	Create(note Note) error
 	GetAll() ([]Note, error)
+	List(query ListQuery) (NotePage, error)
 	GetByID(id string) (Note, error)
-	Update(id string, note Note) error
-	Delete(id string) error
+
+	// Update and Delete apply only if the stored note is at version,
+	// or to any version if it is 0, and fail with ErrVersionMismatch
+	// otherwise. The check and the change are atomic. Update stores
+	// the note with the next version and returns it as stored.
+	Update(id string, note Note, version int64) (Note, error)
+	Delete(id string, version int64) error
 }
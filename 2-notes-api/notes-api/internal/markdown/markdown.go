@@ -0,0 +1,29 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	// md renders GitHub Flavored Markdown (tables, strikethrough,
+	// autolinks, task lists).
+	md = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// policy allows the formatting user content may carry and strips
+	// scripts, event handlers, styles and unsafe URLs.
+	policy = bluemonday.UGCPolicy()
+)
+
+// ToHTML renders Markdown to HTML that is safe to embed in a page.
+// Raw HTML in the source is sanitized rather than trusted.
+func ToHTML(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
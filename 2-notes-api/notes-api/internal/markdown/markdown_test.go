@@ -0,0 +1,61 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "formatting",
+			src:  "# Title\n\nSome **bold** and a [link](https://example.com).",
+			want: []string{"<h1", "Title</h1>", "<strong>bold</strong>", `href="https://example.com"`},
+		},
+		{
+			name: "gfm table",
+			src:  "| a | b |\n|---|---|\n| 1 | 2 |",
+			want: []string{"<table>", "<td>1</td>"},
+		},
+		{
+			name:    "script stripped",
+			src:     "hi <script>alert(1)</script>",
+			notWant: []string{"<script"},
+		},
+		{
+			name:    "javascript url stripped",
+			src:     "[x](javascript:alert(1))",
+			notWant: []string{"javascript:"},
+		},
+		{
+			name:    "event handler stripped",
+			src:     `<img src="a.png" onerror="alert(1)">`,
+			notWant: []string{"onerror"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToHTML(tt.src)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Fatalf("expected %q in %q", w, got)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Fatalf("unexpected %q in %q", w, got)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"slices"
 	"sync"
 
 	"notes-api/internal/domain"
@@ -24,6 +25,10 @@ func (r *MemoryRepository) Create(note domain.Note) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if _, ok := r.notes[note.ID]; ok {
+		return domain.ErrConflict
+	}
+
 	r.notes[note.ID] = note
 	return nil
 }
@@ -39,6 +44,36 @@ func (r *MemoryRepository) GetAll() ([]domain.Note, error) {
 	return result, nil
 }
 
+func (r *MemoryRepository) List(query domain.ListQuery) (domain.NotePage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []domain.Note
+	for _, n := range r.notes {
+		if query.Matches(n) {
+			matches = append(matches, n)
+		}
+	}
+	slices.SortFunc(matches, query.Compare)
+
+	page := domain.NotePage{Total: len(matches)}
+
+	if query.After != nil {
+		i, found := slices.BinarySearchFunc(matches, *query.After, query.Compare)
+		if found {
+			i++
+		}
+		matches = matches[i:]
+	}
+	if query.Limit > 0 && len(matches) > query.Limit {
+		matches = matches[:query.Limit]
+		page.More = true
+	}
+
+	page.Notes = matches
+	return page, nil
+}
+
 func (r *MemoryRepository) GetByID(id string) (domain.Note, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -50,26 +85,35 @@ func (r *MemoryRepository) GetByID(id string) (domain.Note, error) {
 	return note, nil
 }
 
-func (r *MemoryRepository) Update(id string, note domain.Note) error {
+func (r *MemoryRepository) Update(id string, note domain.Note, version int64) (domain.Note, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.notes[id]; !ok {
-		return domain.ErrNotFound
+	stored, ok := r.notes[id]
+	if !ok {
+		return domain.Note{}, domain.ErrNotFound
+	}
+	if version != 0 && stored.Version != version {
+		return domain.Note{}, domain.ErrVersionMismatch
 	}
 
 	note.ID = id
+	note.Version = stored.Version + 1
 	r.notes[id] = note
-	return nil
+	return note, nil
 }
 
-func (r *MemoryRepository) Delete(id string) error {
+func (r *MemoryRepository) Delete(id string, version int64) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.notes[id]; !ok {
+	stored, ok := r.notes[id]
+	if !ok {
 		return domain.ErrNotFound
 	}
+	if version != 0 && stored.Version != version {
+		return domain.ErrVersionMismatch
+	}
 
 	delete(r.notes, id)
 	return nil
@@ -0,0 +1,350 @@
+// Package repotest is a conformance suite for domain.NoteRepository.
+// Every implementation runs it from its own tests, so they all
+// behave the same behind the interface.
+package repotest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"notes-api/internal/domain"
+)
+
+// Run runs the suite. newRepo must return an empty repository;
+// it is called once per subtest.
+func Run(t *testing.T, newRepo func(t *testing.T) domain.NoteRepository) {
+	t.Run("CRUD", func(t *testing.T) {
+		testCRUD(t, newRepo(t))
+	})
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		testCreateDuplicate(t, newRepo(t))
+	})
+	t.Run("GetAll", func(t *testing.T) {
+		testGetAll(t, newRepo(t))
+	})
+	t.Run("List", func(t *testing.T) {
+		testList(t, newRepo(t))
+	})
+	t.Run("Missing", func(t *testing.T) {
+		testMissing(t, newRepo(t))
+	})
+	t.Run("Versions", func(t *testing.T) {
+		testVersions(t, newRepo(t))
+	})
+	t.Run("ConcurrentUpdates", func(t *testing.T) {
+		testConcurrentUpdates(t, newRepo(t))
+	})
+}
+
+func testCRUD(t *testing.T, repo domain.NoteRepository) {
+	created := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)
+	note := domain.Note{
+		ID:        "1",
+		Title:     "Test",
+		Content:   "Body",
+		CreatedAt: created,
+		UpdatedAt: created,
+		Version:   1,
+	}
+
+	// Create
+	if err := repo.Create(note); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// GetByID
+	result, err := repo.GetByID("1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !sameNote(result, note) {
+		t.Fatalf("expected %+v, got %+v", note, result)
+	}
+
+	// Update
+	note.Title = "Updated"
+	note.Content = "New body"
+	note.UpdatedAt = created.Add(time.Hour)
+	returned, err := repo.Update("1", note, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	note.Version = 2
+	updated, _ := repo.GetByID("1")
+	if !sameNote(updated, note) || !sameNote(returned, note) {
+		t.Fatalf("update failed: got %+v, returned %+v", updated, returned)
+	}
+
+	// Delete
+	if err := repo.Delete("1", 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = repo.GetByID("1")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func testCreateDuplicate(t *testing.T, repo domain.NoteRepository) {
+	if err := repo.Create(domain.Note{ID: "1", Title: "First"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := repo.Create(domain.Note{ID: "1", Title: "Second"})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+
+	note, _ := repo.GetByID("1")
+	if note.Title != "First" {
+		t.Fatalf("duplicate create overwrote note: %+v", note)
+	}
+}
+
+func testGetAll(t *testing.T, repo domain.NoteRepository) {
+	notes, err := repo.GetAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notes) != 0 {
+		t.Fatalf("expected no notes, got %d", len(notes))
+	}
+
+	want := []domain.Note{
+		{ID: "1", Title: "One"},
+		{ID: "2", Title: "Two"},
+		{ID: "3", Title: "Three"},
+	}
+	for _, n := range want {
+		if err := repo.Create(n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	notes, err = repo.GetAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Order is not part of the contract.
+	slices.SortFunc(notes, func(a, b domain.Note) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	if !slices.EqualFunc(notes, want, sameNote) {
+		t.Fatalf("expected %+v, got %+v", want, notes)
+	}
+}
+
+// listNotes have tied titles and timestamps, so ordering
+// has to fall back to IDs.
+func listNotes() []domain.Note {
+	t0 := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	return []domain.Note{
+		{ID: "a", Title: "Gopher", CreatedAt: t0, UpdatedAt: t0.Add(5 * time.Hour)},
+		{ID: "b", Title: "Go", CreatedAt: t0.Add(time.Hour), UpdatedAt: t0.Add(time.Hour)},
+		{ID: "c", Title: "go", CreatedAt: t0, UpdatedAt: t0.Add(2 * time.Hour)},
+		{ID: "d", Title: "Über", CreatedAt: t0.Add(2 * time.Hour), UpdatedAt: t0.Add(2 * time.Hour)},
+		{ID: "e", Title: "Go", CreatedAt: t0.Add(3 * time.Hour), UpdatedAt: t0.Add(3 * time.Hour)},
+	}
+}
+
+func testList(t *testing.T, repo domain.NoteRepository) {
+	for _, n := range listNotes() {
+		if err := repo.Create(n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		query   domain.ListQuery
+		wantIDs []string
+	}{
+		{"created", domain.ListQuery{}, []string{"a", "c", "b", "d", "e"}},
+		{"created desc", domain.ListQuery{Desc: true}, []string{"e", "d", "b", "c", "a"}},
+		{"title", domain.ListQuery{Sort: domain.SortTitle}, []string{"b", "e", "a", "c", "d"}},
+		{"title desc", domain.ListQuery{Sort: domain.SortTitle, Desc: true}, []string{"d", "c", "a", "e", "b"}},
+		{"updated", domain.ListQuery{Sort: domain.SortUpdated}, []string{"b", "c", "d", "e", "a"}},
+		{"prefix", domain.ListQuery{Sort: domain.SortTitle, TitlePrefix: "Go"}, []string{"b", "e", "a"}},
+		{"prefix exact case", domain.ListQuery{TitlePrefix: "go"}, []string{"c"}},
+		{"prefix non-ascii", domain.ListQuery{TitlePrefix: "Ü"}, []string{"d"}},
+		{"prefix no match", domain.ListQuery{TitlePrefix: "x"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Whole list at once
+			page, err := repo.List(tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := noteIDs(page.Notes); !slices.Equal(got, tt.wantIDs) {
+				t.Fatalf("expected %v, got %v", tt.wantIDs, got)
+			}
+			if page.Total != len(tt.wantIDs) || page.More {
+				t.Fatalf("expected total %d and no more, got %d, %v", len(tt.wantIDs), page.Total, page.More)
+			}
+
+			// Two at a time
+			q := tt.query
+			q.Limit = 2
+			var got []string
+			for {
+				page, err := repo.List(q)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if page.Total != len(tt.wantIDs) {
+					t.Fatalf("expected total %d, got %d", len(tt.wantIDs), page.Total)
+				}
+				got = append(got, noteIDs(page.Notes)...)
+				if !page.More {
+					break
+				}
+				if len(got) > len(tt.wantIDs) {
+					t.Fatalf("paging does not end: %v", got)
+				}
+				last := page.Notes[len(page.Notes)-1]
+				q.After = &last
+			}
+			if !slices.Equal(got, tt.wantIDs) {
+				t.Fatalf("paged: expected %v, got %v", tt.wantIDs, got)
+			}
+		})
+	}
+
+	// A cursor note deleted between pages still marks the position.
+	t.Run("after deleted", func(t *testing.T) {
+		gone := domain.Note{ID: "bb", CreatedAt: time.Date(2024, 5, 1, 1, 0, 0, 0, time.UTC)}
+		page, err := repo.List(domain.ListQuery{After: &gone})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := noteIDs(page.Notes); !slices.Equal(got, []string{"d", "e"}) {
+			t.Fatalf("expected [d e], got %v", got)
+		}
+	})
+}
+
+func noteIDs(notes []domain.Note) []string {
+	var ids []string
+	for _, n := range notes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+func testMissing(t *testing.T, repo domain.NoteRepository) {
+	if _, err := repo.GetByID("missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByID: expected ErrNotFound, got %v", err)
+	}
+
+	for _, version := range []int64{0, 1} {
+		if _, err := repo.Update("missing", domain.Note{Title: "x"}, version); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Update at %d: expected ErrNotFound, got %v", version, err)
+		}
+
+		if err := repo.Delete("missing", version); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Delete at %d: expected ErrNotFound, got %v", version, err)
+		}
+	}
+}
+
+func testVersions(t *testing.T, repo domain.NoteRepository) {
+	if err := repo.Create(domain.Note{ID: "1", Title: "v1", Version: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Stale version
+	if _, err := repo.Update("1", domain.Note{Title: "x"}, 2); !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("Update: expected ErrVersionMismatch, got %v", err)
+	}
+	if err := repo.Delete("1", 2); !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("Delete: expected ErrVersionMismatch, got %v", err)
+	}
+
+	note, _ := repo.GetByID("1")
+	if note.Title != "v1" || note.Version != 1 {
+		t.Fatalf("failed change modified note: %+v", note)
+	}
+
+	// Version 0 matches any, and the version still advances.
+	for want := int64(2); want <= 3; want++ {
+		note, err := repo.Update("1", domain.Note{Title: "any"}, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if note.Version != want {
+			t.Fatalf("expected version %d, got %d", want, note.Version)
+		}
+	}
+
+	if err := repo.Delete("1", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// testConcurrentUpdates races updates from the same version:
+// exactly one may win.
+func testConcurrentUpdates(t *testing.T, repo domain.NoteRepository) {
+	if err := repo.Create(domain.Note{ID: "1", Title: "v1", Version: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const writers = 8
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		won  []string
+		errs []error
+	)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			title := fmt.Sprintf("writer %d", i)
+			_, err := repo.Update("1", domain.Note{Title: title}, 1)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				won = append(won, title)
+			case !errors.Is(err, domain.ErrVersionMismatch):
+				errs = append(errs, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(won) != 1 {
+		t.Fatalf("expected one winner, got %v", won)
+	}
+
+	note, _ := repo.GetByID("1")
+	if note.Title != won[0] || note.Version != 2 {
+		t.Fatalf("expected %q at version 2, got %+v", won[0], note)
+	}
+}
+
+// sameNote compares notes field by field, with timestamps
+// equal if they denote the same instant.
+func sameNote(a, b domain.Note) bool {
+	return a.ID == b.ID &&
+		a.Title == b.Title &&
+		a.Content == b.Content &&
+		a.CreatedAt.Equal(b.CreatedAt) &&
+		a.UpdatedAt.Equal(b.UpdatedAt) &&
+		a.Version == b.Version
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migrations[v] upgrades the schema from version v to v+1.
+// The current version is kept in PRAGMA user_version.
+// Append new steps; never edit ones that have shipped.
+var migrations = []string{
+	// 1: notes table
+	`CREATE TABLE notes (
+		id      TEXT PRIMARY KEY,
+		title   TEXT NOT NULL,
+		content TEXT NOT NULL DEFAULT ''
+	)`,
+
+	// 2: timestamps in Unix nanoseconds; existing notes get the
+	// time of the upgrade
+	`ALTER TABLE notes ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE notes ADD COLUMN updated_at INTEGER NOT NULL DEFAULT 0;
+	UPDATE notes SET
+		created_at = CAST(unixepoch('subsec') * 1000 AS INTEGER) * 1000000,
+		updated_at = CAST(unixepoch('subsec') * 1000 AS INTEGER) * 1000000`,
+
+	// 3: indexes for listing in each sort order
+	`CREATE INDEX notes_title ON notes (title, id);
+	CREATE INDEX notes_created ON notes (created_at, id);
+	CREATE INDEX notes_updated ON notes (updated_at, id)`,
+
+	// 4: version for optimistic concurrency
+	`ALTER TABLE notes ADD COLUMN version INTEGER NOT NULL DEFAULT 1`,
+}
+
+// migrate applies pending migrations, each in its own transaction
+// together with the version bump.
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return dbError(err)
+	}
+	if version > len(migrations) {
+		return dbError(fmt.Errorf("schema version %d is newer than supported %d", version, len(migrations)))
+	}
+
+	for v := version; v < len(migrations); v++ {
+		if err := migrateStep(db, v); err != nil {
+			return dbError(fmt.Errorf("migration %d: %v", v+1, err))
+		}
+	}
+	return nil
+}
+
+func migrateStep(db *sql.DB, v int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migrations[v]); err != nil {
+		return err
+	}
+	// PRAGMA does not take bound parameters.
+	if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, v+1)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
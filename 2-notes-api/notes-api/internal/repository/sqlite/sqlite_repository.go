@@ -0,0 +1,318 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"notes-api/internal/domain"
+
+	// Pure-Go SQLite driver, registered as "sqlite".
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteRepository is a file-based implementation
+// of the domain.NoteRepository interface.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository opens (or creates) the database at path
+// and brings its schema up to date.
+func NewSQLiteRepository(path string) (*SQLiteRepository, error) {
+	dsn := "file:" + path +
+		"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	// SQLite allows one writer at a time; a single connection
+	// serializes access instead of failing with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteRepository{db: db}, nil
+}
+
+// Close releases the database.
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteRepository) Create(note domain.Note) error {
+	res, err := r.db.Exec(
+		`INSERT INTO notes (id, title, content, created_at, updated_at, version)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (id) DO NOTHING`,
+		note.ID, note.Title, note.Content, toNanos(note.CreatedAt), toNanos(note.UpdatedAt), note.Version,
+	)
+	if err != nil {
+		return dbError(err)
+	}
+
+	return affected(res, domain.ErrConflict)
+}
+
+func (r *SQLiteRepository) GetAll() ([]domain.Note, error) {
+	rows, err := r.db.Query(`SELECT ` + noteColumns + ` FROM notes`)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	var result []domain.Note
+	for rows.Next() {
+		n, err := scanNote(rows)
+		if err != nil {
+			return nil, dbError(err)
+		}
+		result = append(result, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+	return result, nil
+}
+
+func (r *SQLiteRepository) List(query domain.ListQuery) (domain.NotePage, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if query.TitlePrefix != "" {
+		// substr counts characters, not bytes, and unlike LIKE
+		// is case-sensitive with no wildcards to escape.
+		where = append(where, `substr(title, 1, ?) = ?`)
+		args = append(args, utf8.RuneCountInString(query.TitlePrefix), query.TitlePrefix)
+	}
+
+	column, key := sortColumn(query.Sort), sortKey(query.Sort)
+	dir, cmp := "ASC", ">"
+	if query.Desc {
+		dir, cmp = "DESC", "<"
+	}
+
+	// Count and page from the same snapshot.
+	tx, err := r.db.Begin()
+	if err != nil {
+		return domain.NotePage{}, dbError(err)
+	}
+	defer tx.Rollback()
+
+	var page domain.NotePage
+	if err := tx.QueryRow(
+		`SELECT COUNT(*) FROM notes`+whereClause(where), args...,
+	).Scan(&page.Total); err != nil {
+		return domain.NotePage{}, dbError(err)
+	}
+
+	if query.After != nil {
+		where = append(where, fmt.Sprintf(`(%s, id) %s (?, ?)`, column, cmp))
+		args = append(args, key(*query.After), query.After.ID)
+	}
+
+	stmt := fmt.Sprintf(`SELECT %s FROM notes%s ORDER BY %s %s, id %s`,
+		noteColumns, whereClause(where), column, dir, dir)
+	if query.Limit > 0 {
+		// One extra row tells whether another page follows.
+		stmt += ` LIMIT ?`
+		args = append(args, query.Limit+1)
+	}
+
+	rows, err := tx.Query(stmt, args...)
+	if err != nil {
+		return domain.NotePage{}, dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		n, err := scanNote(rows)
+		if err != nil {
+			return domain.NotePage{}, dbError(err)
+		}
+		page.Notes = append(page.Notes, n)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.NotePage{}, dbError(err)
+	}
+
+	if query.Limit > 0 && len(page.Notes) > query.Limit {
+		page.Notes = page.Notes[:query.Limit]
+		page.More = true
+	}
+	return page, nil
+}
+
+func (r *SQLiteRepository) GetByID(id string) (domain.Note, error) {
+	n, err := scanNote(r.db.QueryRow(
+		`SELECT `+noteColumns+` FROM notes WHERE id = ?`, id,
+	))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Note{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Note{}, dbError(err)
+	}
+	return n, nil
+}
+
+func (r *SQLiteRepository) Update(id string, note domain.Note, version int64) (domain.Note, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return domain.Note{}, dbError(err)
+	}
+	defer tx.Rollback()
+
+	updated, err := scanNote(tx.QueryRow(
+		`UPDATE notes
+		 SET title = ?, content = ?, created_at = ?, updated_at = ?, version = version + 1
+		 WHERE id = ? AND (? = 0 OR version = ?)
+		 RETURNING `+noteColumns,
+		note.Title, note.Content, toNanos(note.CreatedAt), toNanos(note.UpdatedAt),
+		id, version, version,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Note{}, missingOrChanged(tx, id)
+	}
+	if err != nil {
+		return domain.Note{}, dbError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Note{}, dbError(err)
+	}
+	return updated, nil
+}
+
+func (r *SQLiteRepository) Delete(id string, version int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return dbError(err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		`DELETE FROM notes WHERE id = ? AND (? = 0 OR version = ?)`,
+		id, version, version,
+	)
+	if err != nil {
+		return dbError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return dbError(err)
+	}
+	if n == 0 {
+		return missingOrChanged(tx, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+// missingOrChanged tells why a conditional statement on id matched no
+// row. It runs in the statement's transaction, so the answer holds
+// for the state the statement saw.
+func missingOrChanged(tx *sql.Tx, id string) error {
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM notes WHERE id = ?)`, id).Scan(&exists); err != nil {
+		return dbError(err)
+	}
+	if exists {
+		return domain.ErrVersionMismatch
+	}
+	return domain.ErrNotFound
+}
+
+// sortColumn is the column holding a sort field.
+func sortColumn(f domain.SortField) string {
+	switch f {
+	case domain.SortTitle:
+		return "title"
+	case domain.SortUpdated:
+		return "updated_at"
+	default:
+		return "created_at"
+	}
+}
+
+// sortKey returns the value sortColumn holds for a note.
+func sortKey(f domain.SortField) func(domain.Note) any {
+	switch f {
+	case domain.SortTitle:
+		return func(n domain.Note) any { return n.Title }
+	case domain.SortUpdated:
+		return func(n domain.Note) any { return toNanos(n.UpdatedAt) }
+	default:
+		return func(n domain.Note) any { return toNanos(n.CreatedAt) }
+	}
+}
+
+func whereClause(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+// noteColumns are the columns read by scanNote, in order.
+const noteColumns = `id, title, content, created_at, updated_at, version`
+
+// scanNote reads a row selected with noteColumns.
+func scanNote(row interface{ Scan(...any) error }) (domain.Note, error) {
+	var (
+		n                domain.Note
+		created, updated int64
+	)
+	if err := row.Scan(&n.ID, &n.Title, &n.Content, &created, &updated, &n.Version); err != nil {
+		return domain.Note{}, err
+	}
+	n.CreatedAt = fromNanos(created)
+	n.UpdatedAt = fromNanos(updated)
+	return n, nil
+}
+
+// Timestamps are stored as Unix nanoseconds, with 0 for the zero time.
+func toNanos(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromNanos(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
+
+// affected returns errNone if the statement changed no rows.
+func affected(res sql.Result, errNone error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return dbError(err)
+	}
+	if n == 0 {
+		return errNone
+	}
+	return nil
+}
+
+// dbError wraps a driver error as domain.ErrDb,
+// keeping the original message for logs.
+func dbError(err error) error {
+	return fmt.Errorf("%w: %v", domain.ErrDb, err)
+}
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository/repotest"
+)
+
+func newTestRepo(t *testing.T, path string) *SQLiteRepository {
+	repo, err := NewSQLiteRepository(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestSQLiteRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) domain.NoteRepository {
+		return newTestRepo(t, filepath.Join(t.TempDir(), "notes.db"))
+	})
+}
+
+func TestSQLiteRepository_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.db")
+
+	repo := newTestRepo(t, path)
+	if err := repo.Create(domain.Note{ID: "1", Title: "Kept"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.Close()
+
+	// Reopening must not re-run migrations or lose data.
+	repo = newTestRepo(t, path)
+	note, err := repo.GetByID("1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if note.Title != "Kept" {
+		t.Fatalf("expected Kept, got %s", note.Title)
+	}
+}
+
+func TestMigrate_NewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.db")
+
+	repo := newTestRepo(t, path)
+	if _, err := repo.db.Exec(`PRAGMA user_version = 999`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.Close()
+
+	if _, err := NewSQLiteRepository(path); err == nil {
+		t.Fatal("expected error for newer schema")
+	}
+}
+
+func TestMigrate_FromVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.db")
+
+	// A database as written by the first schema version.
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, stmt := range []string{
+		migrations[0],
+		`INSERT INTO notes (id, title, content) VALUES ('1', 'Old', 'Body')`,
+		`PRAGMA user_version = 1`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	db.Close()
+
+	before := time.Now()
+	repo := newTestRepo(t, path)
+
+	note, err := repo.GetByID("1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if note.Title != "Old" || note.Content != "Body" {
+		t.Fatalf("data lost in migration: %+v", note)
+	}
+	if note.CreatedAt.Before(before.Add(-time.Second)) || !note.UpdatedAt.Equal(note.CreatedAt) {
+		t.Fatalf("expected timestamps set to upgrade time, got %+v", note)
+	}
+}
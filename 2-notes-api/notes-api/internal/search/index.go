@@ -0,0 +1,242 @@
+package search
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BM25 parameters; titleWeight counts a title word as that many
+// content words.
+const (
+	k1          = 1.2
+	b           = 0.75
+	titleWeight = 2
+)
+
+// prefixWeight scales the score of a term matched only by prefix,
+// so "go" ranks a note saying "go" above one saying "golang".
+const prefixWeight = 0.8
+
+// Index is an in-memory inverted index over documents with a title
+// and content, ranked with BM25. It is safe for concurrent use.
+type Index struct {
+	mu sync.RWMutex
+
+	// postings[term][id] is the weighted frequency of term in a document.
+	postings map[string]map[string]float64
+
+	// terms holds the keys of postings in order, for prefix lookups.
+	terms []string
+
+	docs     map[string]doc
+	totalLen float64
+}
+
+type doc struct {
+	length float64
+	terms  []string
+}
+
+// Hit is a document matching a search.
+type Hit struct {
+	ID    string
+	Score float64
+
+	// Terms are the indexed terms that matched, for highlighting.
+	Terms []string
+}
+
+// New returns an empty index.
+func New() *Index {
+	return &Index{
+		postings: make(map[string]map[string]float64),
+		docs:     make(map[string]doc),
+	}
+}
+
+// Add indexes a document, replacing any earlier one with the same id.
+func (ix *Index) Add(id, title, content string) {
+	freq := make(map[string]float64)
+	var length float64
+	for _, t := range tokenize(title) {
+		freq[t.term] += titleWeight
+		length += titleWeight
+	}
+	for _, t := range tokenize(content) {
+		freq[t.term]++
+		length++
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+
+	d := doc{length: length, terms: make([]string, 0, len(freq))}
+	for term, f := range freq {
+		p, ok := ix.postings[term]
+		if !ok {
+			p = make(map[string]float64)
+			ix.postings[term] = p
+			i, _ := slices.BinarySearch(ix.terms, term)
+			ix.terms = slices.Insert(ix.terms, i, term)
+		}
+		p[id] = f
+		d.terms = append(d.terms, term)
+	}
+	ix.docs[id] = d
+	ix.totalLen += length
+}
+
+// Remove drops a document from the index.
+func (ix *Index) Remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+}
+
+func (ix *Index) remove(id string) {
+	d, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+
+	for _, term := range d.terms {
+		p := ix.postings[term]
+		delete(p, id)
+		if len(p) == 0 {
+			delete(ix.postings, term)
+			if i, found := slices.BinarySearch(ix.terms, term); found {
+				ix.terms = slices.Delete(ix.terms, i, i+1)
+			}
+		}
+	}
+	delete(ix.docs, id)
+	ix.totalLen -= d.length
+}
+
+// Len returns the number of indexed documents.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.docs)
+}
+
+// Search finds the documents containing every word of query, each as
+// a whole word or a prefix of one, best first. It returns up to limit
+// hits (all if limit is 0) and the number of matching documents.
+func (ix *Index) Search(query string, limit int) ([]Hit, int) {
+	words := Terms(query)
+	if len(words) == 0 {
+		return nil, 0
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var (
+		scores  map[string]float64
+		matched = make(map[string][]string)
+	)
+	for _, word := range words {
+		// Best score of any term the word matches, per document.
+		// The idf is that of the word, so a rare completion of a
+		// common prefix does not outrank the word itself.
+		terms := ix.expand(word)
+		idf := ix.idf(terms)
+		best := make(map[string]float64)
+		for _, term := range terms {
+			weight := 1.0
+			if term != word {
+				weight = prefixWeight
+			}
+			for id, tf := range ix.postings[term] {
+				if scores != nil {
+					if _, ok := scores[id]; !ok {
+						continue
+					}
+				}
+				best[id] = max(best[id], weight*idf*ix.saturate(tf, id))
+				matched[id] = append(matched[id], term)
+			}
+		}
+
+		// AND: keep documents matched by every word so far.
+		if scores == nil {
+			scores = best
+			continue
+		}
+		for id := range scores {
+			if s, ok := best[id]; ok {
+				scores[id] += s
+			} else {
+				delete(scores, id)
+			}
+		}
+		if len(scores) == 0 {
+			return nil, 0
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		terms := matched[id]
+		slices.Sort(terms)
+		hits = append(hits, Hit{ID: id, Score: score, Terms: slices.Compact(terms)})
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	total := len(hits)
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, total
+}
+
+// expand returns the indexed terms starting with word.
+func (ix *Index) expand(word string) []string {
+	i, _ := slices.BinarySearch(ix.terms, word)
+	j := i
+	for j < len(ix.terms) && strings.HasPrefix(ix.terms[j], word) {
+		j++
+	}
+	return ix.terms[i:j]
+}
+
+// idf is the inverse document frequency of documents
+// containing any of terms.
+func (ix *Index) idf(terms []string) float64 {
+	if len(terms) == 1 {
+		return idf(len(ix.docs), len(ix.postings[terms[0]]))
+	}
+
+	containing := make(map[string]bool)
+	for _, term := range terms {
+		for id := range ix.postings[term] {
+			containing[id] = true
+		}
+	}
+	return idf(len(ix.docs), len(containing))
+}
+
+func idf(n, df int) float64 {
+	return math.Log(1 + (float64(n)-float64(df)+0.5)/(float64(df)+0.5))
+}
+
+// saturate is the BM25 term frequency component, normalized by
+// document length.
+func (ix *Index) saturate(tf float64, id string) float64 {
+	avg := ix.totalLen / float64(len(ix.docs))
+	norm := 1 - b + b*ix.docs[id].length/avg
+	return tf * (k1 + 1) / (tf + k1*norm)
+}
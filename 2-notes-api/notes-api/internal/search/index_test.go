@@ -0,0 +1,98 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func hitIDs(hits []Hit) []string {
+	var ids []string
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+func newTestIndex() *Index {
+	ix := New()
+	ix.Add("1", "Go tips", "Use gofmt. Go is simple.")
+	ix.Add("2", "Golang generics", "Type parameters in golang.")
+	ix.Add("3", "Shopping", "Milk, eggs, bread.")
+	ix.Add("4", "Travel", "Pack bread and a go bag.")
+	return ix
+}
+
+func TestIndex_Search(t *testing.T) {
+	ix := newTestIndex()
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"exact ranks above prefix", "go", []string{"1", "2", "4"}},
+		{"whole word", "golang", []string{"2"}},
+		{"prefix", "gener", []string{"2"}},
+		{"and", "go bread", []string{"4"}},
+		{"and no match", "milk golang", nil},
+		{"case and punctuation", "MILK!", []string{"3"}},
+		{"unknown", "python", nil},
+		{"empty", "  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, total := ix.Search(tt.query, 0)
+			if got := hitIDs(hits); !slices.Equal(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			if total != len(tt.want) {
+				t.Fatalf("expected total %d, got %d", len(tt.want), total)
+			}
+		})
+	}
+}
+
+func TestIndex_Limit(t *testing.T) {
+	ix := newTestIndex()
+
+	hits, total := ix.Search("go", 2)
+	if !slices.Equal(hitIDs(hits), []string{"1", "2"}) || total != 3 {
+		t.Fatalf("expected [1 2] of 3, got %v of %d", hitIDs(hits), total)
+	}
+}
+
+func TestIndex_MatchedTerms(t *testing.T) {
+	ix := newTestIndex()
+
+	hits, _ := ix.Search("go", 0)
+	if !slices.Equal(hits[0].Terms, []string{"go", "gofmt"}) {
+		t.Fatalf("expected [go gofmt], got %v", hits[0].Terms)
+	}
+}
+
+func TestIndex_UpdateAndRemove(t *testing.T) {
+	ix := newTestIndex()
+
+	ix.Add("3", "Shopping", "Coffee and golang stickers.")
+	if hits, _ := ix.Search("milk", 0); len(hits) != 0 {
+		t.Fatalf("old content still indexed: %v", hitIDs(hits))
+	}
+	if hits, _ := ix.Search("coffee", 0); !slices.Equal(hitIDs(hits), []string{"3"}) {
+		t.Fatalf("new content not indexed: %v", hitIDs(hits))
+	}
+
+	ix.Remove("3")
+	ix.Remove("missing")
+	if hits, _ := ix.Search("coffee", 0); len(hits) != 0 {
+		t.Fatalf("removed document found: %v", hitIDs(hits))
+	}
+	if ix.Len() != 3 {
+		t.Fatalf("expected 3 documents, got %d", ix.Len())
+	}
+
+	// Terms only the removed document had are gone.
+	if slices.Contains(ix.terms, "coffee") || len(ix.terms) != len(ix.postings) {
+		t.Fatalf("stale terms: %v", ix.terms)
+	}
+}
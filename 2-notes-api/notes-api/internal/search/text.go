@@ -0,0 +1,105 @@
+package search
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a word of a text: its lowercased form and byte offsets.
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into words of letters and digits.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			tokens = append(tokens, token{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+// Terms returns the distinct words of a query as they are matched.
+func Terms(query string) []string {
+	var terms []string
+	for _, t := range tokenize(query) {
+		if !slices.Contains(terms, t.term) {
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
+
+// Snippet returns an excerpt of about width characters of text around
+// the first of terms it contains, as HTML: the text is escaped and each
+// word in terms is wrapped in <mark>. Cut ends are marked with "…".
+// Without a match the excerpt is the start of text and ok is false.
+func Snippet(text string, terms []string, width int) (snippet string, ok bool) {
+	tokens := tokenize(text)
+	first := slices.IndexFunc(tokens, func(t token) bool {
+		return slices.Contains(terms, t.term)
+	})
+
+	// Start a little before the first match, on a word boundary.
+	from := 0
+	if first >= 0 {
+		from = tokens[first].start
+		for i := first - 1; i >= 0; i-- {
+			if utf8.RuneCountInString(text[tokens[i].start:tokens[first].start]) > width/4 {
+				break
+			}
+			from = tokens[i].start
+		}
+	}
+
+	// End after width characters, on a word boundary if there is one.
+	to, n := len(text), 0
+	for i := range text[from:] {
+		if n == width {
+			to = from + i
+			break
+		}
+		n++
+	}
+	if to < len(text) {
+		if i := strings.LastIndexFunc(text[from:to], unicode.IsSpace); i > 0 {
+			to = from + i
+		}
+	}
+
+	var sb strings.Builder
+	if from > 0 {
+		sb.WriteString("…")
+	}
+	pos := from
+	for _, t := range tokens {
+		if t.start < from || t.end > to || !slices.Contains(terms, t.term) {
+			continue
+		}
+		sb.WriteString(html.EscapeString(text[pos:t.start]))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(text[t.start:t.end]))
+		sb.WriteString("</mark>")
+		pos = t.end
+	}
+	sb.WriteString(html.EscapeString(strings.TrimRightFunc(text[pos:to], unicode.IsSpace)))
+	if to < len(text) {
+		sb.WriteString("…")
+	}
+	return sb.String(), first >= 0
+}
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	got := Terms("Foo-bar, FOO  Über 42")
+	want := []string{"foo", "bar", "über", "42"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		width int
+		want  string
+		noHit bool
+	}{
+		{
+			name:  "short text",
+			text:  "Go is simple",
+			terms: []string{"go"},
+			width: 40,
+			want:  "<mark>Go</mark> is simple",
+		},
+		{
+			name:  "escaped",
+			text:  "use <b> & go",
+			terms: []string{"go"},
+			width: 40,
+			want:  "use &lt;b&gt; &amp; <mark>go</mark>",
+		},
+		{
+			name:  "window around match",
+			text:  "one two three four five six seven eight nine ten golang eleven twelve thirteen fourteen",
+			terms: []string{"golang"},
+			width: 24,
+			want:  "…ten <mark>golang</mark> eleven…",
+		},
+		{
+			name:  "no match",
+			text:  "alpha beta gamma delta",
+			terms: []string{"zeta"},
+			width: 12,
+			want:  "alpha beta…",
+			noHit: true,
+		},
+		{
+			name:  "whole words only",
+			text:  "gopher go",
+			terms: []string{"go"},
+			width: 40,
+			want:  "gopher <mark>go</mark>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Snippet(tt.text, tt.terms, tt.width)
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+			if ok == tt.noHit {
+				t.Fatalf("expected match %v", !tt.noHit)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"notes-api/internal/domain"
+)
+
+// cursor is the position after the last note of a page. Clients get
+// it as an opaque string; it records the order it was made for so it
+// is not applied to a different one.
+type cursor struct {
+	Sort  domain.SortField `json:"s"`
+	Desc  bool             `json:"d,omitempty"`
+	ID    string           `json:"id"`
+	Title string           `json:"t,omitempty"`
+	Time  time.Time        `json:"ts"`
+}
+
+func encodeCursor(sort domain.SortField, desc bool, last domain.Note) string {
+	c := cursor{Sort: sort, Desc: desc, ID: last.ID}
+	switch sort {
+	case domain.SortTitle:
+		c.Title = last.Title
+	case domain.SortUpdated:
+		c.Time = last.UpdatedAt
+	default:
+		c.Time = last.CreatedAt
+	}
+
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the note to continue after, with only its ID
+// and sort field set.
+func decodeCursor(s string, sort domain.SortField, desc bool) (*domain.Note, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if c.Sort != sort || c.Desc != desc {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return &domain.Note{
+		ID:        c.ID,
+		Title:     c.Title,
+		CreatedAt: c.Time,
+		UpdatedAt: c.Time,
+	}, nil
+}
@@ -1,27 +1,84 @@
 package usecase
 
 import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
 	"notes-api/internal/domain"
+	"notes-api/internal/search"
 )
 
 // NoteUsecase contains business logic.
 // It depends only on domain interfaces.
 type NoteUsecase struct {
 	repo domain.NoteRepository
+
+	// index is the full-text index of all notes. Writes hold mu from
+	// the repository change to the index update, so the index sees
+	// changes in the order the repository made them.
+	index *search.Index
+	mu    sync.Mutex
+
+	// now and newID are replaced in tests.
+	now   func() time.Time
+	newID func() string
 }
 
 // NewNoteUsecase injects repository dependency.
+// Call Reindex to index notes already in the repository.
 func NewNoteUsecase(repo domain.NoteRepository) *NoteUsecase {
-	return &NoteUsecase{repo: repo}
+	return &NoteUsecase{
+		repo:  repo,
+		index: search.New(),
+		now:   func() time.Time { return time.Now().UTC() },
+		newID: uuid.NewString,
+	}
 }
 
-// Create validates and creates a note.
-func (u *NoteUsecase) Create(note domain.Note) error {
-	if note.ID == "" || note.Title == "" {
-		return domain.ErrInvalidInput
+// Reindex rebuilds the search index from the repository.
+func (u *NoteUsecase) Reindex() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	notes, err := u.repo.GetAll()
+	if err != nil {
+		return err
 	}
 
-	return u.repo.Create(note)
+	index := search.New()
+	for _, n := range notes {
+		index.Add(n.ID, n.Title, n.Content)
+	}
+	u.index = index
+	return nil
+}
+
+// Create validates and creates a note. A missing ID is generated;
+// timestamps and version are set here and any given by the caller
+// are ignored. It returns the note as stored.
+func (u *NoteUsecase) Create(note domain.Note) (domain.Note, error) {
+	if note.Title == "" {
+		return domain.Note{}, domain.ErrInvalidInput
+	}
+
+	if note.ID == "" {
+		note.ID = u.newID()
+	}
+	note.CreatedAt = u.now()
+	note.UpdatedAt = note.CreatedAt
+	note.Version = 1
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err := u.repo.Create(note); err != nil {
+		return domain.Note{}, err
+	}
+	u.index.Add(note.ID, note.Title, note.Content)
+	return note, nil
 }
 
 // GetAll retrieves all notes.
@@ -29,20 +86,168 @@ func (u *NoteUsecase) GetAll() ([]domain.Note, error) {
 	return u.repo.GetAll()
 }
 
+// Page sizes for List.
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ListOptions selects a page of notes.
+type ListOptions struct {
+	TitlePrefix string
+	Sort        domain.SortField // default domain.SortCreated
+	Desc        bool
+
+	// Cursor is NextCursor of the previous page, empty for the first.
+	// It must come from a listing with the same Sort and Desc.
+	Cursor string
+
+	// Limit is the page size, DefaultLimit if 0.
+	Limit int
+}
+
+// ListResult is a page of notes.
+type ListResult struct {
+	Notes []domain.Note
+	Total int
+
+	// NextCursor continues the listing; empty on the last page.
+	NextCursor string
+}
+
+// List returns a page of notes, ordered and filtered by opts.
+func (u *NoteUsecase) List(opts ListOptions) (ListResult, error) {
+	if opts.Sort == "" {
+		opts.Sort = domain.SortCreated
+	}
+	if opts.Limit == 0 {
+		opts.Limit = DefaultLimit
+	}
+	if !opts.Sort.Valid() || opts.Limit < 0 || opts.Limit > MaxLimit {
+		return ListResult{}, domain.ErrInvalidInput
+	}
+
+	query := domain.ListQuery{
+		TitlePrefix: opts.TitlePrefix,
+		Sort:        opts.Sort,
+		Desc:        opts.Desc,
+		Limit:       opts.Limit,
+	}
+	if opts.Cursor != "" {
+		after, err := decodeCursor(opts.Cursor, opts.Sort, opts.Desc)
+		if err != nil {
+			return ListResult{}, err
+		}
+		query.After = after
+	}
+
+	page, err := u.repo.List(query)
+	if err != nil {
+		return ListResult{}, err
+	}
+
+	result := ListResult{Notes: page.Notes, Total: page.Total}
+	if page.More && len(page.Notes) > 0 {
+		result.NextCursor = encodeCursor(opts.Sort, opts.Desc, page.Notes[len(page.Notes)-1])
+	}
+	return result, nil
+}
+
 // GetByID retrieves a note by ID.
 func (u *NoteUsecase) GetByID(id string) (domain.Note, error) {
 	return u.repo.GetByID(id)
 }
 
-// Update updates a note.
-func (u *NoteUsecase) Update(id string, note domain.Note) error {
+// Update replaces title and content of a note, keeping its
+// creation time. With version other than 0 the note must still be at
+// that version, else domain.ErrVersionMismatch is returned.
+// It returns the note as stored.
+func (u *NoteUsecase) Update(id string, note domain.Note, version int64) (domain.Note, error) {
 	if note.Title == "" {
-		return domain.ErrInvalidInput
+		return domain.Note{}, domain.ErrInvalidInput
 	}
-	return u.repo.Update(id, note)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	existing, err := u.repo.GetByID(id)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	note.ID = id
+	note.CreatedAt = existing.CreatedAt
+	note.UpdatedAt = u.now()
+
+	note, err = u.repo.Update(id, note, version)
+	if err != nil {
+		return domain.Note{}, err
+	}
+	u.index.Add(note.ID, note.Title, note.Content)
+	return note, nil
 }
 
-// Delete removes a note.
-func (u *NoteUsecase) Delete(id string) error {
-	return u.repo.Delete(id)
+// Delete removes a note. With version other than 0 the note must
+// still be at that version, else domain.ErrVersionMismatch is returned.
+func (u *NoteUsecase) Delete(id string, version int64) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err := u.repo.Delete(id, version); err != nil {
+		return err
+	}
+	u.index.Remove(id)
+	return nil
+}
+
+// snippetWidth is the length of search result snippets in characters.
+const snippetWidth = 160
+
+// SearchResult is a note found by Search.
+type SearchResult struct {
+	Note  domain.Note
+	Score float64
+
+	// Snippet is an HTML excerpt of the content, or of the title if
+	// only that matched, with the matching words in <mark>.
+	Snippet string
+}
+
+// Search finds the notes whose title or content contains every word of
+// query, as a whole word or a prefix, ranked by relevance (BM25).
+// It returns up to limit results (DefaultLimit if 0) and the number of
+// matching notes.
+func (u *NoteUsecase) Search(query string, limit int) ([]SearchResult, int, error) {
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if len(search.Terms(query)) == 0 || limit < 0 || limit > MaxLimit {
+		return nil, 0, domain.ErrInvalidInput
+	}
+
+	u.mu.Lock()
+	index := u.index
+	u.mu.Unlock()
+
+	hits, total := index.Search(query, limit)
+
+	results := make([]SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		note, err := u.repo.GetByID(hit.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			// Deleted since the search.
+			total--
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		snippet, ok := search.Snippet(note.Content, hit.Terms, snippetWidth)
+		if !ok {
+			snippet, _ = search.Snippet(note.Title, hit.Terms, snippetWidth)
+		}
+		results = append(results, SearchResult{Note: note, Score: hit.Score, Snippet: snippet})
+	}
+	return results, total, nil
 }
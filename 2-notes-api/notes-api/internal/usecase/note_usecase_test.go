@@ -3,6 +3,7 @@ package usecase
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"notes-api/internal/domain"
 )
@@ -11,9 +12,10 @@ import (
 type mockRepo struct {
 	createFn  func(note domain.Note) error
 	getAllFn  func() ([]domain.Note, error)
+	listFn    func(query domain.ListQuery) (domain.NotePage, error)
 	getByIDFn func(id string) (domain.Note, error)
-	updateFn  func(id string, note domain.Note) error
-	deleteFn  func(id string) error
+	updateFn  func(id string, note domain.Note, version int64) (domain.Note, error)
+	deleteFn  func(id string, version int64) error
 }
 
 func (m *mockRepo) Create(note domain.Note) error {
@@ -24,16 +26,30 @@ func (m *mockRepo) GetAll() ([]domain.Note, error) {
 	return m.getAllFn()
 }
 
+func (m *mockRepo) List(query domain.ListQuery) (domain.NotePage, error) {
+	return m.listFn(query)
+}
+
 func (m *mockRepo) GetByID(id string) (domain.Note, error) {
 	return m.getByIDFn(id)
 }
 
-func (m *mockRepo) Update(id string, note domain.Note) error {
-	return m.updateFn(id, note)
+func (m *mockRepo) Update(id string, note domain.Note, version int64) (domain.Note, error) {
+	return m.updateFn(id, note, version)
 }
 
-func (m *mockRepo) Delete(id string) error {
-	return m.deleteFn(id)
+func (m *mockRepo) Delete(id string, version int64) error {
+	return m.deleteFn(id, version)
+}
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestUsecase returns a usecase with a fixed clock and ID generator.
+func newTestUsecase(repo domain.NoteRepository) *NoteUsecase {
+	uc := NewNoteUsecase(repo)
+	uc.now = func() time.Time { return testNow }
+	uc.newID = func() string { return "generated" }
+	return uc
 }
 
 func TestCreate(t *testing.T) {
@@ -41,6 +57,7 @@ func TestCreate(t *testing.T) {
 		name    string
 		note    domain.Note
 		repoErr error
+		wantID  string
 		wantErr error
 	}{
 		{
@@ -49,15 +66,32 @@ func TestCreate(t *testing.T) {
 				ID:    "1",
 				Title: "Test",
 			},
+			wantID:  "1",
 			wantErr: nil,
 		},
 		{
-			name: "missing id",
+			name: "missing id is generated",
 			note: domain.Note{
 				Title: "Test",
 			},
+			wantID: "generated",
+		},
+		{
+			name: "missing title",
+			note: domain.Note{
+				ID: "1",
+			},
 			wantErr: domain.ErrInvalidInput,
 		},
+		{
+			name: "client timestamps ignored",
+			note: domain.Note{
+				ID:        "1",
+				Title:     "Test",
+				CreatedAt: testNow.Add(-time.Hour),
+			},
+			wantID: "1",
+		},
 		{
 			name: "repo failure",
 			note: domain.Note{
@@ -72,15 +106,17 @@ func TestCreate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
+			var stored domain.Note
 			mock := &mockRepo{
 				createFn: func(note domain.Note) error {
+					stored = note
 					return tt.repoErr
 				},
 			}
 
-			uc := NewNoteUsecase(mock)
+			uc := newTestUsecase(mock)
 
-			err := uc.Create(tt.note)
+			got, err := uc.Create(tt.note)
 
 			if tt.wantErr == nil && err != nil {
 				t.Fatalf("unexpected error: %v", err)
@@ -89,6 +125,22 @@ func TestCreate(t *testing.T) {
 			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
 				t.Fatalf("expected %v, got %v", tt.wantErr, err)
 			}
+
+			if tt.wantErr != nil {
+				return
+			}
+			if got != stored {
+				t.Fatalf("returned %+v, stored %+v", got, stored)
+			}
+			if got.ID != tt.wantID {
+				t.Fatalf("expected ID %s, got %s", tt.wantID, got.ID)
+			}
+			if !got.CreatedAt.Equal(testNow) || !got.UpdatedAt.Equal(testNow) {
+				t.Fatalf("expected timestamps %v, got %+v", testNow, got)
+			}
+			if got.Version != 1 {
+				t.Fatalf("expected version 1, got %d", got.Version)
+			}
 		})
 	}
 }
@@ -155,6 +207,122 @@ func TestGetAll(t *testing.T) {
 	}
 }
 
+func TestList(t *testing.T) {
+	last := domain.Note{ID: "2", Title: "B", CreatedAt: testNow}
+	titleCursor := encodeCursor(domain.SortTitle, false, last)
+
+	tests := []struct {
+		name       string
+		opts       ListOptions
+		page       domain.NotePage
+		wantQuery  domain.ListQuery
+		wantCursor bool
+		wantErr    error
+	}{
+		{
+			name:      "defaults",
+			page:      domain.NotePage{Notes: []domain.Note{last}, Total: 1},
+			wantQuery: domain.ListQuery{Sort: domain.SortCreated, Limit: DefaultLimit},
+		},
+		{
+			name:       "more pages",
+			opts:       ListOptions{Sort: domain.SortTitle, Limit: 1},
+			page:       domain.NotePage{Notes: []domain.Note{last}, Total: 3, More: true},
+			wantQuery:  domain.ListQuery{Sort: domain.SortTitle, Limit: 1},
+			wantCursor: true,
+		},
+		{
+			name: "cursor",
+			opts: ListOptions{Sort: domain.SortTitle, TitlePrefix: "B", Cursor: titleCursor},
+			wantQuery: domain.ListQuery{
+				Sort:        domain.SortTitle,
+				TitlePrefix: "B",
+				Limit:       DefaultLimit,
+				After:       &domain.Note{ID: "2", Title: "B"},
+			},
+		},
+		{
+			name:    "cursor for other order",
+			opts:    ListOptions{Sort: domain.SortTitle, Desc: true, Cursor: titleCursor},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "malformed cursor",
+			opts:    ListOptions{Cursor: "!!"},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "unknown sort",
+			opts:    ListOptions{Sort: "size"},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "limit too large",
+			opts:    ListOptions{Limit: MaxLimit + 1},
+			wantErr: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var got domain.ListQuery
+			mock := &mockRepo{
+				listFn: func(query domain.ListQuery) (domain.NotePage, error) {
+					got = query
+					return tt.page, nil
+				},
+			}
+
+			uc := newTestUsecase(mock)
+
+			result, err := uc.List(tt.opts)
+
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+			if (got.After == nil) != (tt.wantQuery.After == nil) ||
+				got.After != nil && (got.After.ID != tt.wantQuery.After.ID || got.After.Title != tt.wantQuery.After.Title) {
+				t.Fatalf("expected after %+v, got %+v", tt.wantQuery.After, got.After)
+			}
+			got.After, tt.wantQuery.After = nil, nil
+			if got != tt.wantQuery {
+				t.Fatalf("expected query %+v, got %+v", tt.wantQuery, got)
+			}
+			if result.Total != tt.page.Total {
+				t.Fatalf("expected total %d, got %d", tt.page.Total, result.Total)
+			}
+			if (result.NextCursor != "") != tt.wantCursor {
+				t.Fatalf("unexpected next cursor %q", result.NextCursor)
+			}
+		})
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	last := domain.Note{ID: "7", Title: "T", CreatedAt: testNow, UpdatedAt: testNow.Add(time.Hour)}
+
+	for _, sort := range []domain.SortField{domain.SortTitle, domain.SortCreated, domain.SortUpdated} {
+		after, err := decodeCursor(encodeCursor(sort, true, last), sort, true)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", sort, err)
+		}
+
+		query := domain.ListQuery{Sort: sort, Desc: true}
+		if query.Compare(*after, last) != 0 {
+			t.Fatalf("%s: cursor %+v does not mark %+v", sort, after, last)
+		}
+	}
+}
+
 func TestGetByID(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -203,6 +371,8 @@ func TestUpdate(t *testing.T) {
 		name    string
 		id      string
 		note    domain.Note
+		version int64
+		getErr  error
 		repoErr error
 		wantErr error
 	}{
@@ -214,15 +384,47 @@ func TestUpdate(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "at version",
+			id:   "1",
+			note: domain.Note{
+				Title: "Test",
+			},
+			version: 3,
+		},
+		{
+			name: "version mismatch",
+			id:   "1",
+			note: domain.Note{
+				Title: "Test",
+			},
+			version: 2,
+			repoErr: domain.ErrVersionMismatch,
+			wantErr: domain.ErrVersionMismatch,
+		},
 		{
 			name: "not found",
 			id:   "1",
 			note: domain.Note{
 				Title: "Test",
 			},
+			getErr:  domain.ErrNotFound,
+			wantErr: domain.ErrNotFound,
+		},
+		{
+			name: "deleted concurrently",
+			id:   "1",
+			note: domain.Note{
+				Title: "Test",
+			},
 			repoErr: domain.ErrNotFound,
 			wantErr: domain.ErrNotFound,
 		},
+		{
+			name:    "missing title",
+			id:      "1",
+			wantErr: domain.ErrInvalidInput,
+		},
 		{
 			name: "repo failure",
 			id:   "1",
@@ -237,15 +439,25 @@ func TestUpdate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
+			created := testNow.Add(-time.Hour)
+			var (
+				stored  domain.Note
+				version int64
+			)
 			mock := &mockRepo{
-				updateFn: func(id string, note domain.Note) error {
-					return tt.repoErr
+				getByIDFn: func(id string) (domain.Note, error) {
+					return domain.Note{ID: id, CreatedAt: created, Version: 3}, tt.getErr
+				},
+				updateFn: func(id string, note domain.Note, v int64) (domain.Note, error) {
+					stored, version = note, v
+					note.Version = 4
+					return note, tt.repoErr
 				},
 			}
 
-			uc := NewNoteUsecase(mock)
+			uc := newTestUsecase(mock)
 
-			err := uc.Update(tt.id, tt.note)
+			got, err := uc.Update(tt.id, tt.note, tt.version)
 
 			if tt.wantErr == nil && err != nil {
 				t.Fatalf("unexpected error: %v", err)
@@ -254,6 +466,19 @@ func TestUpdate(t *testing.T) {
 			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
 				t.Fatalf("expected %v, got %v", tt.wantErr, err)
 			}
+
+			if tt.wantErr != nil {
+				return
+			}
+			if version != tt.version {
+				t.Fatalf("expected version %d passed to repo, got %d", tt.version, version)
+			}
+			if got.Version != 4 || got.Title != stored.Title {
+				t.Fatalf("expected note as stored by repo, got %+v", got)
+			}
+			if !got.CreatedAt.Equal(created) || !got.UpdatedAt.Equal(testNow) {
+				t.Fatalf("expected created %v and updated %v, got %+v", created, testNow, got)
+			}
 		})
 	}
 }
@@ -262,6 +487,7 @@ func TestDelete(t *testing.T) {
 	tests := []struct {
 		name    string
 		id      string
+		version int64
 		repoErr error
 		wantErr error
 	}{
@@ -275,20 +501,30 @@ func TestDelete(t *testing.T) {
 			repoErr: domain.ErrNotFound,
 			wantErr: domain.ErrNotFound,
 		},
+		{
+			name:    "version mismatch",
+			id:      "1",
+			version: 2,
+			repoErr: domain.ErrVersionMismatch,
+			wantErr: domain.ErrVersionMismatch,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
 			mock := &mockRepo{
-				deleteFn: func(id string) error {
+				deleteFn: func(id string, version int64) error {
+					if version != tt.version {
+						t.Fatalf("expected version %d passed to repo, got %d", tt.version, version)
+					}
 					return tt.repoErr
 				},
 			}
 
 			uc := NewNoteUsecase(mock)
 
-			err := uc.Delete(tt.id)
+			err := uc.Delete(tt.id, tt.version)
 
 			if tt.wantErr == nil && err != nil {
 				t.Fatalf("unexpected error: %v", err)
@@ -300,3 +536,118 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+// mapRepo returns a mockRepo keeping notes in a map, for tests
+// that go through several operations.
+func mapRepo(notes map[string]domain.Note) *mockRepo {
+	return &mockRepo{
+		createFn: func(note domain.Note) error {
+			notes[note.ID] = note
+			return nil
+		},
+		getAllFn: func() ([]domain.Note, error) {
+			var all []domain.Note
+			for _, n := range notes {
+				all = append(all, n)
+			}
+			return all, nil
+		},
+		getByIDFn: func(id string) (domain.Note, error) {
+			n, ok := notes[id]
+			if !ok {
+				return domain.Note{}, domain.ErrNotFound
+			}
+			return n, nil
+		},
+		updateFn: func(id string, note domain.Note, version int64) (domain.Note, error) {
+			note.Version = notes[id].Version + 1
+			notes[id] = note
+			return note, nil
+		},
+		deleteFn: func(id string, version int64) error {
+			delete(notes, id)
+			return nil
+		},
+	}
+}
+
+func TestSearch(t *testing.T) {
+	notes := map[string]domain.Note{
+		"old": {ID: "old", Title: "Existing", Content: "Indexed by reindex."},
+	}
+	uc := newTestUsecase(mapRepo(notes))
+
+	if err := uc.Reindex(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	uc.newID = func() string { return "a" }
+	if _, err := uc.Create(domain.Note{Title: "Go tips", Content: "Run gofmt before every commit."}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	uc.newID = func() string { return "b" }
+	if _, err := uc.Create(domain.Note{Title: "Groceries", Content: "Milk and <eggs>."}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	search := func(query string) ([]SearchResult, int) {
+		t.Helper()
+		results, total, err := uc.Search(query, 0)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", query, err)
+		}
+		return results, total
+	}
+	ids := func(results []SearchResult) []string {
+		var ids []string
+		for _, r := range results {
+			ids = append(ids, r.Note.ID)
+		}
+		return ids
+	}
+
+	if results, _ := search("reindex"); len(results) != 1 || results[0].Note.ID != "old" {
+		t.Fatalf("reindexed note not found: %v", ids(results))
+	}
+
+	results, total := search("gof commit")
+	if total != 1 || len(results) != 1 || results[0].Note.ID != "a" {
+		t.Fatalf("expected [a], got %v of %d", ids(results), total)
+	}
+	if want := "Run <mark>gofmt</mark> before every <mark>commit</mark>."; results[0].Snippet != want {
+		t.Fatalf("expected snippet %q, got %q", want, results[0].Snippet)
+	}
+
+	// Only the title matches
+	if results, _ := search("groceries"); len(results) != 1 || results[0].Snippet != "<mark>Groceries</mark>" {
+		t.Fatalf("expected title snippet, got %+v", results)
+	}
+
+	// Update replaces the indexed text
+	if _, err := uc.Update("b", domain.Note{Title: "Groceries", Content: "Bread"}, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results, _ := search("milk"); len(results) != 0 {
+		t.Fatalf("stale content found: %v", ids(results))
+	}
+	if results, _ := search("bread"); len(results) != 1 {
+		t.Fatalf("updated content not found: %v", ids(results))
+	}
+
+	// Delete removes the note
+	if err := uc.Delete("a", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results, total := search("gofmt"); len(results) != 0 || total != 0 {
+		t.Fatalf("deleted note found: %v", ids(results))
+	}
+
+	// Invalid queries
+	for _, q := range []string{"", " -- "} {
+		if _, _, err := uc.Search(q, 0); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("%q: expected ErrInvalidInput, got %v", q, err)
+		}
+	}
+	if _, _, err := uc.Search("go", MaxLimit+1); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for limit, got %v", err)
+	}
+}